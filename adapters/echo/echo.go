@@ -1,6 +1,8 @@
 package echo
 
 import (
+	"net/http"
+
 	"github.com/hiiamtin/goctxid"
 	"github.com/labstack/echo/v4"
 )
@@ -51,27 +53,40 @@ func New(config ...Config) echo.MiddlewareFunc {
 				return next(c)
 			}
 
-			// 4. Extract the correlation ID from the request header
-			correlationID := c.Request().Header.Get(cfg.HeaderKey)
-
-			// 5. If not found, generate a new one
-			if correlationID == "" {
-				correlationID = cfg.Generator()
+			// 4. Extract the correlation ID from the request header,
+			// validating it and generating a new one if needed
+			// RemoteIP is the direct peer, not c.RealIP() which trusts X-Forwarded-For by default
+			req := c.Request()
+			res, err := cfg.ResolveRequest(req.Context(), goctxid.RequestInfo{
+				Method:     req.Method,
+				Path:       req.URL.Path,
+				RemoteAddr: req.RemoteAddr,
+				RemoteIP:   goctxid.ParseRemoteIP(req.RemoteAddr),
+				Header:     req.Header.Get,
+			})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 			}
 
-			// 6. Set the response header (send back to the client)
-			c.Response().Header().Set(cfg.HeaderKey, correlationID)
+			// 5. Set the response header (send back to the client)
+			c.Response().Header().Set(cfg.HeaderKey, res.ID)
+			if res.TraceParent.IsValid() {
+				c.Response().Header().Set(goctxid.TraceParentHeader, res.TraceParent.String())
+			}
+			if res.RequestID != "" && cfg.RequestIDHeaderKey != "" {
+				c.Response().Header().Set(cfg.RequestIDHeaderKey, res.RequestID)
+			}
 
-			// 7. Get the current request context
+			// 6. Get the current request context
 			ctx := c.Request().Context()
 
-			// 8. Create a new context with our ID
-			newCtx := goctxid.NewContext(ctx, correlationID)
+			// 7. Create a new context with our ID
+			newCtx := res.NewContext(ctx)
 
-			// 9. Set the new context back into the request
+			// 8. Set the new context back into the request
 			c.SetRequest(c.Request().WithContext(newCtx))
 
-			// 10. Continue to the next handler
+			// 9. Continue to the next handler
 			return next(c)
 		}
 	}
@@ -83,14 +98,13 @@ func New(config ...Config) echo.MiddlewareFunc {
 //
 // ⚠️ GOROUTINE SAFETY WARNING:
 // Do NOT pass echo.Context directly into goroutines! Echo may reuse context objects.
-// Instead, pass the context.Context or copy the correlation ID value:
+// Instead, use goctxid.Go (or goctxid.NewGroup / goctxid.NewPool) or copy the ID:
 //
-//	// ✅ CORRECT - Option 1: Pass context.Context
-//	ctx := c.Request().Context()
-//	go func(ctx context.Context) {
+//	// ✅ CORRECT - Option 1: goctxid.Go (the context outlives the request)
+//	goctxid.Go(c.Request().Context(), func(ctx context.Context) {
 //	    id := MustFromContext(ctx)
 //	    log.Printf("ID: %s", id)
-//	}(ctx)
+//	})
 //
 //	// ✅ CORRECT - Option 2: Copy the value
 //	correlationID := GetCorrelationID(c)
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"sync"
 	"testing"
 	"time"
@@ -749,4 +750,491 @@ func TestReExportedFunctions(t *testing.T) {
 	if id2 == "" {
 		t.Error("FastGenerator should return non-empty ID")
 	}
+
+	id3 := UUIDv7Generator()
+	if id3 == "" {
+		t.Error("UUIDv7Generator should return non-empty ID")
+	}
+
+	id4 := ULIDGenerator()
+	if id4 == "" {
+		t.Error("ULIDGenerator should return non-empty ID")
+	}
+
+	id5 := FastOpaqueGenerator()
+	if id5 == "" {
+		t.Error("FastOpaqueGenerator should return non-empty ID")
+	}
+
+	id6 := BufferedGenerator()
+	if id6 == "" {
+		t.Error("BufferedGenerator should return non-empty ID")
+	}
+}
+
+// TestValidator tests how invalid inbound IDs are handled by each policy
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          goctxid.InvalidPolicy
+		inbound         string
+		expectedStatus  int
+		expectedID      string
+		expectedInvalid bool
+	}{
+		{
+			name:           "accepts valid ID",
+			policy:         goctxid.InvalidReject,
+			inbound:        "123e4567-e89b-12d3-a456-426614174000",
+			expectedStatus: http.StatusOK,
+			expectedID:     "123e4567-e89b-12d3-a456-426614174000",
+		},
+		{
+			name:           "regenerates invalid ID",
+			policy:         goctxid.InvalidRegenerate,
+			inbound:        "not-a-uuid",
+			expectedStatus: http.StatusOK,
+			expectedID:     "generated-id",
+		},
+		{
+			name:           "rejects invalid ID",
+			policy:         goctxid.InvalidReject,
+			inbound:        "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:            "keeps and flags invalid ID",
+			policy:          goctxid.InvalidKeep,
+			inbound:         "not-a-uuid",
+			expectedStatus:  http.StatusOK,
+			expectedID:      "not-a-uuid",
+			expectedInvalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(New(Config{
+				Config: goctxid.Config{
+					Generator: func() string { return "generated-id" },
+					Validator: goctxid.ValidateUUID,
+					OnInvalid: tt.policy,
+				},
+			}))
+
+			var contextID string
+			var invalid bool
+			e.GET("/test", func(c echo.Context) error {
+				contextID = GetCorrelationID(c)
+				invalid = IsInvalid(c.Request().Context())
+				return c.String(http.StatusOK, "OK")
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set(DefaultHeaderKey, tt.inbound)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			if responseID := rec.Header().Get(DefaultHeaderKey); responseID != tt.expectedID {
+				t.Errorf("Response header ID = %v, want %v", responseID, tt.expectedID)
+			}
+			if invalid != tt.expectedInvalid {
+				t.Errorf("IsInvalid = %v, want %v", invalid, tt.expectedInvalid)
+			}
+		})
+	}
+}
+
+func TestTraceParent(t *testing.T) {
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	tests := []struct {
+		name               string
+		config             goctxid.Config
+		inboundTraceParent string
+		expectedID         string
+		expectedEmit       bool
+	}{
+		{
+			name:               "ignores traceparent by default",
+			config:             goctxid.Config{},
+			inboundTraceParent: traceParent,
+			expectedID:         "generated-id",
+		},
+		{
+			name:               "derives ID from traceparent",
+			config:             goctxid.Config{UseTraceParent: true},
+			inboundTraceParent: traceParent,
+			expectedID:         traceID,
+		},
+		{
+			name:               "continues inbound trace",
+			config:             goctxid.Config{UseTraceParent: true, EmitTraceParent: true},
+			inboundTraceParent: traceParent,
+			expectedID:         traceID,
+			expectedEmit:       true,
+		},
+		{
+			name:         "starts new trace",
+			config:       goctxid.Config{EmitTraceParent: true},
+			expectedID:   "generated-id",
+			expectedEmit: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.config
+			cfg.Generator = func() string { return "generated-id" }
+			e := echo.New()
+			e.Use(New(Config{Config: cfg}))
+
+			var contextID string
+			var contextTraceParent goctxid.TraceParent
+			var ok bool
+			e.GET("/test", func(c echo.Context) error {
+				contextID = GetCorrelationID(c)
+				contextTraceParent, ok = goctxid.TraceParentFromContext(c.Request().Context())
+				return c.String(http.StatusOK, "OK")
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			if tt.inboundTraceParent != "" {
+				req.Header.Set(goctxid.TraceParentHeader, tt.inboundTraceParent)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			if ok != tt.expectedEmit {
+				t.Fatalf("Traceparent in context = %v, want %v", ok, tt.expectedEmit)
+			}
+
+			responseTraceParent := rec.Header().Get(goctxid.TraceParentHeader)
+			if !tt.expectedEmit {
+				if responseTraceParent != "" {
+					t.Errorf("Unexpected traceparent response header %v", responseTraceParent)
+				}
+				return
+			}
+			if responseTraceParent != contextTraceParent.String() {
+				t.Errorf("Response traceparent = %v, want %v", responseTraceParent, contextTraceParent.String())
+			}
+			if tt.inboundTraceParent != "" {
+				if contextTraceParent.TraceIDString() != traceID {
+					t.Errorf("Trace ID = %v, want %v", contextTraceParent.TraceIDString(), traceID)
+				}
+				if responseTraceParent == tt.inboundTraceParent {
+					t.Error("Expected a new parent-id in the response traceparent")
+				}
+			}
+		})
+	}
+}
+
+func TestInboundHeaderKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		expectedID string
+	}{
+		{
+			name:       "uses first listed header",
+			headers:    map[string]string{DefaultHeaderKey: "correlation-id", "X-Request-ID": "request-id"},
+			expectedID: "correlation-id",
+		},
+		{
+			name:       "falls back to later header",
+			headers:    map[string]string{"X-Request-ID": "request-id"},
+			expectedID: "request-id",
+		},
+		{
+			name:       "generates ID without listed headers",
+			headers:    map[string]string{"X-Other-ID": "other-id"},
+			expectedID: "generated-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := goctxid.Config{
+				InboundHeaderKeys: []string{DefaultHeaderKey, "X-Request-ID"},
+				Generator:         func() string { return "generated-id" },
+			}
+			e := echo.New()
+			e.Use(New(Config{Config: cfg}))
+
+			var contextID string
+			e.GET("/test", func(c echo.Context) error {
+				contextID = GetCorrelationID(c)
+				return c.String(http.StatusOK, "OK")
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			// The response always uses the canonical header
+			if responseID := rec.Header().Get(DefaultHeaderKey); responseID != tt.expectedID {
+				t.Errorf("Response header ID = %v, want %v", responseID, tt.expectedID)
+			}
+		})
+	}
+}
+
+// TestRequestGenerator tests that RequestGenerator sees the request
+func TestRequestGenerator(t *testing.T) {
+	var got goctxid.RequestInfo
+	cfg := goctxid.Config{
+		RequestGenerator: func(ctx context.Context, r goctxid.RequestInfo) string {
+			got = r
+			return r.Header("X-Tenant") + "-generated"
+		},
+	}
+
+	tests := []struct {
+		name       string
+		inbound    string
+		expectedID string
+	}{
+		{name: "generates ID from request", inbound: "", expectedID: "acme-generated"},
+		{name: "keeps inbound ID", inbound: "client-id", expectedID: "client-id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = goctxid.RequestInfo{}
+			var contextID string
+			e := echo.New()
+			e.Use(New(Config{Config: cfg}))
+			e.POST("/test", func(c echo.Context) error {
+				contextID = GetCorrelationID(c)
+				return c.String(http.StatusOK, "OK")
+			})
+
+			req := httptest.NewRequest("POST", "/test", nil)
+			req.Header.Set("X-Tenant", "acme")
+			if tt.inbound != "" {
+				req.Header.Set(DefaultHeaderKey, tt.inbound)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			if responseID := rec.Header().Get(DefaultHeaderKey); responseID != tt.expectedID {
+				t.Errorf("Response header ID = %v, want %v", responseID, tt.expectedID)
+			}
+			if tt.inbound != "" {
+				if got.Header != nil {
+					t.Error("RequestGenerator should not be called for an inbound ID")
+				}
+				return
+			}
+			if got.Method != "POST" || got.Path != "/test" {
+				t.Errorf("RequestInfo = %s %s, want POST /test", got.Method, got.Path)
+			}
+			if got.RemoteAddr == "" {
+				t.Error("RequestInfo.RemoteAddr is empty")
+			}
+		})
+	}
+}
+
+// TestFromContextWithInfo tests that the origin of the correlation ID is recorded
+func TestFromContextWithInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		expectedInfo goctxid.IDInfo
+	}{
+		{
+			name:         "generated",
+			headers:      map[string]string{},
+			expectedInfo: goctxid.IDInfo{ID: "generated-id", Origin: OriginGenerated},
+		},
+		{
+			name:         "from inbound header",
+			headers:      map[string]string{"X-Request-ID": "request-id"},
+			expectedInfo: goctxid.IDInfo{ID: "request-id", Origin: OriginHeader, HeaderKey: "X-Request-ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := goctxid.Config{
+				InboundHeaderKeys: []string{DefaultHeaderKey, "X-Request-ID"},
+				Generator:         func() string { return "generated-id" },
+			}
+			var info goctxid.IDInfo
+			e := echo.New()
+			e.Use(New(Config{Config: cfg}))
+			e.GET("/test", func(c echo.Context) error {
+				info, _ = FromContextWithInfo(c.Request().Context())
+				return c.String(http.StatusOK, "OK")
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			e.ServeHTTP(httptest.NewRecorder(), req)
+
+			if info != tt.expectedInfo {
+				t.Errorf("FromContextWithInfo() = %+v, want %+v", info, tt.expectedInfo)
+			}
+		})
+	}
+}
+
+// TestRequestID tests the per-service request ID next to the upstream correlation ID
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name             string
+		headerKey        string
+		expectedInHeader string
+	}{
+		{name: "echoes request ID", headerKey: "X-Request-ID", expectedInHeader: "request-id"},
+		{name: "does not echo request ID without header key", headerKey: "", expectedInHeader: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := goctxid.Config{
+				RequestIDGenerator: func() string { return "request-id" },
+				RequestIDHeaderKey: tt.headerKey,
+			}
+			var contextID, requestID string
+			e := echo.New()
+			e.Use(New(Config{Config: cfg}))
+			e.GET("/test", func(c echo.Context) error {
+				contextID = GetCorrelationID(c)
+				requestID = MustRequestIDFromContext(c.Request().Context())
+				return c.String(http.StatusOK, "OK")
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set(DefaultHeaderKey, "upstream-id")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if contextID != "upstream-id" {
+				t.Errorf("Context ID = %v, want %v", contextID, "upstream-id")
+			}
+			if requestID != "request-id" {
+				t.Errorf("Request ID = %v, want %v", requestID, "request-id")
+			}
+			if responseID := rec.Header().Get(DefaultHeaderKey); responseID != "upstream-id" {
+				t.Errorf("Response header ID = %v, want %v", responseID, "upstream-id")
+			}
+			if got := rec.Header().Get("X-Request-ID"); got != tt.expectedInHeader {
+				t.Errorf("Response request ID = %v, want %v", got, tt.expectedInHeader)
+			}
+		})
+	}
+}
+
+func TestTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name         string
+		trusted      string
+		forwardedFor string
+		expectedID   string
+	}{
+		{name: "honors ID from trusted proxy", trusted: "192.0.2.1/32", expectedID: "upstream-id"},
+		{name: "regenerates ID from untrusted peer", trusted: "10.0.0.0/8", expectedID: "generated-id"},
+		{name: "ignores spoofed X-Forwarded-For", trusted: "10.0.0.0/8", forwardedFor: "10.0.0.1", expectedID: "generated-id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := goctxid.Config{
+				Generator:      func() string { return "generated-id" },
+				TrustedProxies: []netip.Prefix{netip.MustParsePrefix(tt.trusted)},
+			}
+			var contextID string
+			e := echo.New()
+			e.Use(New(Config{Config: cfg}))
+			e.GET("/test", func(c echo.Context) error {
+				contextID = GetCorrelationID(c)
+				return c.String(http.StatusOK, "OK")
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set(DefaultHeaderKey, "upstream-id")
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			if responseID := rec.Header().Get(DefaultHeaderKey); responseID != tt.expectedID {
+				t.Errorf("Response header ID = %v, want %v", responseID, tt.expectedID)
+			}
+		})
+	}
+}
+
+func TestSigner(t *testing.T) {
+	signer, err := goctxid.NewSigner([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		inbound    string
+		expectedID string
+	}{
+		{name: "honors signed ID", inbound: signer.Sign("upstream-id"), expectedID: signer.Sign("upstream-id")},
+		{name: "regenerates forged ID", inbound: "upstream-id.AAAAAAAAAAAAAAAA", expectedID: signer.Sign("generated-id")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := goctxid.Config{
+				Generator: func() string { return "generated-id" },
+				Signer:    signer,
+			}
+			var contextID string
+			e := echo.New()
+			e.Use(New(Config{Config: cfg}))
+			e.GET("/test", func(c echo.Context) error {
+				contextID = GetCorrelationID(c)
+				return c.String(http.StatusOK, "OK")
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set(DefaultHeaderKey, tt.inbound)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			if responseID := rec.Header().Get(DefaultHeaderKey); responseID != tt.expectedID {
+				t.Errorf("Response header ID = %v, want %v", responseID, tt.expectedID)
+			}
+		})
+	}
 }
@@ -1,7 +1,11 @@
 package fiber
 
 import (
+	"context"
+	"net/netip"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/hiiamtin/goctxid"
 )
 
@@ -44,6 +48,9 @@ func New(config ...Config) fiber.Handler {
 	// 1. Merge the provided config with the default config
 	cfg := configDefault(config...)
 
+	// The request line and peer address are only read when a callback can see them
+	needsRequest := cfg.RequestGenerator != nil || cfg.TrustInbound != nil || len(cfg.TrustedProxies) > 0
+
 	// 2. Return the middleware function
 	return func(c *fiber.Ctx) error {
 		// 3. Check if we should skip this middleware
@@ -51,27 +58,46 @@ func New(config ...Config) fiber.Handler {
 			return c.Next()
 		}
 
-		// 4. Extract the correlation ID from the request header
-		correlationID := c.Get(cfg.HeaderKey)
-
-		// 5. If not found, generate a new one
-		if correlationID == "" {
-			correlationID = cfg.Generator()
+		// 4. Extract the correlation ID from the request header,
+		// validating it and generating a new one if needed
+		// c.Get, c.Method and c.Path return strings backed by fasthttp's request
+		// buffer, which is reused for the next request on the connection; copy
+		// them before they can be stored in the context or Locals
+		r := goctxid.RequestInfo{
+			Header: func(key string) string { return utils.CopyString(c.Get(key)) },
+		}
+		if needsRequest {
+			r.Method = utils.CopyString(c.Method())
+			r.Path = utils.CopyString(c.Path())
+			r.RemoteAddr = c.Context().RemoteAddr().String()
+			// RemoteIP is the direct peer, not c.IP() which reads ProxyHeader when it is configured
+			remoteIP, _ := netip.AddrFromSlice(c.Context().RemoteIP())
+			r.RemoteIP = remoteIP.Unmap()
+		}
+		res, err := cfg.ResolveRequest(c.UserContext(), r)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
 		}
 
-		// 6. Set the response header (send back to the client)
-		c.Set(cfg.HeaderKey, correlationID)
+		// 5. Set the response header (send back to the client)
+		c.Set(cfg.HeaderKey, res.ID)
+		if res.TraceParent.IsValid() {
+			c.Set(goctxid.TraceParentHeader, res.TraceParent.String())
+		}
+		if res.RequestID != "" && cfg.RequestIDHeaderKey != "" {
+			c.Set(cfg.RequestIDHeaderKey, res.RequestID)
+		}
 
-		// 7. Get the current user context
+		// 6. Get the current user context
 		ctx := c.UserContext()
 
-		// 8. Create a new context with our ID (using helper from goctxid.go)
-		newCtx := goctxid.NewContext(ctx, correlationID)
+		// 7. Create a new context with our ID (using helper from goctxid.go)
+		newCtx := res.NewContext(ctx)
 
-		// 9. Set the new context back into Fiber
+		// 8. Set the new context back into Fiber
 		c.SetUserContext(newCtx)
 
-		// 10. Continue to the next handler
+		// 9. Continue to the next handler
 		return c.Next()
 	}
 }
@@ -82,14 +108,14 @@ func New(config ...Config) fiber.Handler {
 //
 // ⚠️ GOROUTINE SAFETY WARNING:
 // Do NOT pass *fiber.Ctx directly into goroutines! Fiber recycles context objects.
-// Instead, pass the context.Context or copy the correlation ID value:
+// Instead, take a DetachedContext (c.UserContext() may hold values tied to c)
+// or copy the ID:
 //
-//	// ✅ CORRECT - Option 1: Pass context.Context
-//	ctx := c.UserContext()
-//	go func(ctx context.Context) {
+//	// ✅ CORRECT - Option 1: Detached context
+//	goctxid.Go(DetachedContext(c), func(ctx context.Context) {
 //	    id := MustFromContext(ctx)
 //	    log.Printf("ID: %s", id)
-//	}(ctx)
+//	})
 //
 //	// ✅ CORRECT - Option 2: Copy the value
 //	correlationID := GetCorrelationID(c)
@@ -104,3 +130,16 @@ func New(config ...Config) fiber.Handler {
 func GetCorrelationID(c *fiber.Ctx) string {
 	return MustFromContext(c.UserContext())
 }
+
+// DetachedContext returns a new context.Context carrying the correlation ID and
+// the other goctxid values of c.UserContext() (see goctxid.Snapshot). It keeps no
+// reference to c or to values that other middleware stored in the user context,
+// so it can be passed to goroutines and ctx-aware clients after the handler returns.
+//
+//	ctx := DetachedContext(c)
+//	go func() {
+//	    publishEvent(ctx, event) // Still carries the correlation ID
+//	}()
+func DetachedContext(c *fiber.Ctx) context.Context {
+	return goctxid.Snapshot(c.UserContext())
+}
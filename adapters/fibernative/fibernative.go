@@ -1,13 +1,33 @@
 package fibernative
 
 import (
+	"context"
+	"net/netip"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/hiiamtin/goctxid"
 )
 
 const (
 	// DefaultLocalsKey is the default key used to store the correlation ID in c.Locals()
 	DefaultLocalsKey = "goctxid"
+
+	// InvalidLocalsSuffix is appended to the LocalsKey to flag an inbound
+	// correlation ID that failed validation and was kept (goctxid.InvalidKeep)
+	InvalidLocalsSuffix = ".invalid"
+
+	// TraceParentLocalsSuffix is appended to the LocalsKey to store the
+	// traceparent when goctxid.Config.EmitTraceParent is enabled
+	TraceParentLocalsSuffix = ".traceparent"
+
+	// InfoLocalsSuffix is appended to the LocalsKey to store the goctxid.IDInfo
+	// (where the correlation ID came from)
+	InfoLocalsSuffix = ".info"
+
+	// RequestIDLocalsSuffix is appended to the LocalsKey to store the per-service
+	// request ID when goctxid.Config.RequestIDGenerator is configured
+	RequestIDLocalsSuffix = ".request_id"
 )
 
 // Config extends goctxid.Config with Fiber-native specific options
@@ -59,6 +79,19 @@ func New(config ...Config) fiber.Handler {
 	// 1. Merge the provided config with the default config
 	cfg := configDefault(config...)
 
+	// The request line and peer address are only read when a callback can see them
+	needsRequest := cfg.RequestGenerator != nil || cfg.TrustInbound != nil || len(cfg.TrustedProxies) > 0
+
+	// Build the Locals keys once: concatenating the suffixes and converting the
+	// keys to interface{} would otherwise allocate on every request
+	var (
+		localsKey      any = cfg.LocalsKey
+		infoKey        any = cfg.LocalsKey + InfoLocalsSuffix
+		invalidKey     any = cfg.LocalsKey + InvalidLocalsSuffix
+		traceParentKey any = cfg.LocalsKey + TraceParentLocalsSuffix
+		requestIDKey   any = cfg.LocalsKey + RequestIDLocalsSuffix
+	)
+
 	// 2. Return the middleware function
 	return func(c *fiber.Ctx) error {
 		// 3. Check if we should skip this middleware
@@ -66,21 +99,50 @@ func New(config ...Config) fiber.Handler {
 			return c.Next()
 		}
 
-		// 4. Extract the correlation ID from the request header
-		correlationID := c.Get(cfg.HeaderKey)
-
-		// 5. If not found, generate a new one
-		if correlationID == "" {
-			correlationID = cfg.Generator()
+		// 4. Extract the correlation ID from the request header,
+		// validating it and generating a new one if needed
+		// c.Get, c.Method and c.Path return strings backed by fasthttp's request
+		// buffer, which is reused for the next request on the connection; copy
+		// them before they can be stored in the context or Locals
+		r := goctxid.RequestInfo{
+			Header: func(key string) string { return utils.CopyString(c.Get(key)) },
+		}
+		if needsRequest {
+			r.Method = utils.CopyString(c.Method())
+			r.Path = utils.CopyString(c.Path())
+			r.RemoteAddr = c.Context().RemoteAddr().String()
+			// RemoteIP is the direct peer, not c.IP() which reads ProxyHeader when it is configured
+			remoteIP, _ := netip.AddrFromSlice(c.Context().RemoteIP())
+			r.RemoteIP = remoteIP.Unmap()
+		}
+		res, err := cfg.ResolveRequest(c.UserContext(), r)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
 		}
 
-		// 6. Set the response header (send back to the client)
-		c.Set(cfg.HeaderKey, correlationID)
+		// 5. Set the response header (send back to the client)
+		c.Set(cfg.HeaderKey, res.ID)
+		if res.TraceParent.IsValid() {
+			c.Set(goctxid.TraceParentHeader, res.TraceParent.String())
+		}
+		if res.RequestID != "" && cfg.RequestIDHeaderKey != "" {
+			c.Set(cfg.RequestIDHeaderKey, res.RequestID)
+		}
 
-		// 7. Store in Fiber's Locals (Fiber-native way - no context overhead)
-		c.Locals(cfg.LocalsKey, correlationID)
+		// 6. Store in Fiber's Locals (Fiber-native way - no context overhead)
+		c.Locals(localsKey, res.ID)
+		c.Locals(infoKey, res.Info())
+		if res.Invalid {
+			c.Locals(invalidKey, true)
+		}
+		if res.TraceParent.IsValid() {
+			c.Locals(traceParentKey, res.TraceParent)
+		}
+		if res.RequestID != "" {
+			c.Locals(requestIDKey, res.RequestID)
+		}
 
-		// 8. Continue to the next handler
+		// 7. Continue to the next handler
 		return c.Next()
 	}
 }
@@ -117,15 +179,108 @@ func MustFromLocalsWithKey(c *fiber.Ctx, key string) string {
 	return id
 }
 
+// FromLocalsWithInfo retrieves the correlation ID from c.Locals() together with
+// where it came from (inbound header, generated or traceparent). Uses the default key.
+func FromLocalsWithInfo(c *fiber.Ctx) (goctxid.IDInfo, bool) {
+	return FromLocalsWithInfoAndKey(c, DefaultLocalsKey)
+}
+
+// FromLocalsWithInfoAndKey is like FromLocalsWithInfo but uses a custom LocalsKey.
+// The origin is goctxid.OriginUnknown when the ID was not stored by the middleware.
+func FromLocalsWithInfoAndKey(c *fiber.Ctx, key string) (goctxid.IDInfo, bool) {
+	id, ok := FromLocalsWithKey(c, key)
+	if !ok {
+		return goctxid.IDInfo{}, false
+	}
+	// The ID may have been replaced since it was resolved
+	if info, ok := c.Locals(key + InfoLocalsSuffix).(goctxid.IDInfo); ok && info.ID == id {
+		return info, true
+	}
+	return goctxid.IDInfo{ID: id}, true
+}
+
+// IsInvalid reports whether the correlation ID in c.Locals() came from the request
+// and failed validation (only possible with goctxid.InvalidKeep). Uses the default key.
+func IsInvalid(c *fiber.Ctx) bool {
+	return IsInvalidWithKey(c, DefaultLocalsKey)
+}
+
+// IsInvalidWithKey is like IsInvalid but uses a custom LocalsKey.
+func IsInvalidWithKey(c *fiber.Ctx, key string) bool {
+	invalid, _ := c.Locals(key + InvalidLocalsSuffix).(bool)
+	return invalid
+}
+
+// TraceParentFromLocals retrieves the traceparent stored in c.Locals() when
+// goctxid.Config.EmitTraceParent is enabled. Uses the default key.
+func TraceParentFromLocals(c *fiber.Ctx) (goctxid.TraceParent, bool) {
+	return TraceParentFromLocalsWithKey(c, DefaultLocalsKey)
+}
+
+// TraceParentFromLocalsWithKey is like TraceParentFromLocals but uses a custom LocalsKey.
+func TraceParentFromLocalsWithKey(c *fiber.Ctx, key string) (goctxid.TraceParent, bool) {
+	tp, ok := c.Locals(key + TraceParentLocalsSuffix).(goctxid.TraceParent)
+	return tp, ok
+}
+
+// RequestIDFromLocals retrieves the per-service request ID stored in c.Locals()
+// when goctxid.Config.RequestIDGenerator is configured. Uses the default key.
+func RequestIDFromLocals(c *fiber.Ctx) (string, bool) {
+	return RequestIDFromLocalsWithKey(c, DefaultLocalsKey)
+}
+
+// RequestIDFromLocalsWithKey is like RequestIDFromLocals but uses a custom LocalsKey.
+func RequestIDFromLocalsWithKey(c *fiber.Ctx, key string) (string, bool) {
+	id, ok := c.Locals(key + RequestIDLocalsSuffix).(string)
+	return id, ok
+}
+
+// DetachedContext returns a new context.Context carrying the correlation ID
+// stored in c.Locals(), with its info, request ID and traceparent, so it can be
+// read with goctxid.FromContext. It keeps no reference to c, so it can be passed
+// to goroutines and ctx-aware clients after the handler returns. Returns
+// context.Background() if there is no correlation ID. Uses the default key.
+//
+//	ctx := DetachedContext(c)
+//	go func() {
+//	    publishEvent(ctx, event) // goctxid.MustFromContext(ctx) is the ID
+//	}()
+func DetachedContext(c *fiber.Ctx) context.Context {
+	return DetachedContextWithKey(c, DefaultLocalsKey)
+}
+
+// DetachedContextWithKey is like DetachedContext but uses a custom LocalsKey.
+func DetachedContextWithKey(c *fiber.Ctx, key string) context.Context {
+	ctx := context.Background()
+	info, ok := FromLocalsWithInfoAndKey(c, key)
+	if !ok {
+		return ctx
+	}
+	res := goctxid.Resolution{
+		ID:        info.ID,
+		Invalid:   info.Invalid,
+		Origin:    info.Origin,
+		HeaderKey: info.HeaderKey,
+	}
+	res.RequestID, _ = RequestIDFromLocalsWithKey(c, key)
+	res.TraceParent, _ = TraceParentFromLocalsWithKey(c, key)
+	return res.NewContext(ctx)
+}
+
 // GetCorrelationID retrieves the correlation ID from the Fiber Local.
 // Returns the correlation ID or an empty string if not found.
 // This is a convenience function equivalent to MustFromLocals(c).
 //
 // ⚠️ GOROUTINE SAFETY WARNING:
 // Do NOT pass *fiber.Ctx directly into goroutines! Fiber recycles context objects.
-// Always copy the correlation ID value before using it in a goroutine:
+// Copy the correlation ID value, or take a DetachedContext, before starting a goroutine:
+//
+//	// ✅ CORRECT - Option 1: Detached context
+//	goctxid.Go(DetachedContext(c), func(ctx context.Context) {
+//	    log.Printf("ID: %s", goctxid.MustFromContext(ctx))
+//	})
 //
-//	// ✅ CORRECT:
+//	// ✅ CORRECT - Option 2: Copy the value
 //	correlationID := GetCorrelationID(c)
 //	go func(id string) {
 //	    log.Printf("ID: %s", id)
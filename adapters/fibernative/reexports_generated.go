@@ -10,6 +10,13 @@ import (
 const (
 	// DefaultHeaderKey is the default HTTP header key for correlation ID
 	DefaultHeaderKey = goctxid.DefaultHeaderKey
+
+	// OriginUnknown, OriginHeader, OriginGenerated and OriginTraceParent tell
+	// where a correlation ID came from (see goctxid.IDInfo)
+	OriginUnknown     = goctxid.OriginUnknown
+	OriginHeader      = goctxid.OriginHeader
+	OriginGenerated   = goctxid.OriginGenerated
+	OriginTraceParent = goctxid.OriginTraceParent
 )
 
 // Re-exported generator functions from goctxid package for convenience
@@ -17,9 +24,24 @@ var (
 	// DefaultGenerator is the default UUID v4 generator (cryptographically secure)
 	DefaultGenerator = goctxid.DefaultGenerator
 
+	// BufferedGenerator is a UUID v4 generator that reads crypto/rand in batches
+	BufferedGenerator = goctxid.BufferedGenerator
+
 	// FastGenerator is a high-performance generator using atomic counter
 	// ⚠️ WARNING: Exposes request count. Use only when performance is critical.
 	FastGenerator = goctxid.FastGenerator
+
+	// AppendFastID appends a FastGenerator ID to a buffer without allocating
+	AppendFastID = goctxid.AppendFastID
+
+	// FastOpaqueGenerator is a high-performance generator that hides its counter
+	FastOpaqueGenerator = goctxid.FastOpaqueGenerator
+
+	// UUIDv7Generator is a time-ordered UUID v7 generator (index-friendly)
+	UUIDv7Generator = goctxid.UUIDv7Generator
+
+	// ULIDGenerator is a lexicographically sortable ULID generator (26 characters)
+	ULIDGenerator = goctxid.ULIDGenerator
 )
 
 // NOTE: Context-based functions (FromContext, MustFromContext, NewContext) are NOT re-exported
@@ -30,5 +52,9 @@ var (
 //   - MustFromLocals(c *fiber.Ctx) string
 //   - FromLocalsWithKey(c *fiber.Ctx, key string) (string, bool)
 //   - MustFromLocalsWithKey(c *fiber.Ctx, key string) string
+//   - FromLocalsWithInfo(c *fiber.Ctx) (goctxid.IDInfo, bool)
+//   - RequestIDFromLocals(c *fiber.Ctx) (string, bool)
+//   - DetachedContext(c *fiber.Ctx) context.Context
+//   - IsInvalid(c *fiber.Ctx) bool
 //
 // If you need context-based storage for goroutine safety, use the adapters/fiber package instead.
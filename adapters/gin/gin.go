@@ -1,6 +1,8 @@
 package gin
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/hiiamtin/goctxid"
 )
@@ -51,27 +53,40 @@ func New(config ...Config) gin.HandlerFunc {
 			return
 		}
 
-		// 4. Extract the correlation ID from the request header
-		correlationID := c.GetHeader(cfg.HeaderKey)
-
-		// 5. If not found, generate a new one
-		if correlationID == "" {
-			correlationID = cfg.Generator()
+		// 4. Extract the correlation ID from the request header,
+		// validating it and generating a new one if needed
+		// RemoteIP is the direct peer, not c.ClientIP() which trusts X-Forwarded-For by default
+		res, err := cfg.ResolveRequest(c.Request.Context(), goctxid.RequestInfo{
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			RemoteAddr: c.Request.RemoteAddr,
+			RemoteIP:   goctxid.ParseRemoteIP(c.RemoteIP()),
+			Header:     c.GetHeader,
+		})
+		if err != nil {
+			_ = c.AbortWithError(http.StatusBadRequest, err)
+			return
 		}
 
-		// 6. Set the response header (send back to the client)
-		c.Header(cfg.HeaderKey, correlationID)
+		// 5. Set the response header (send back to the client)
+		c.Header(cfg.HeaderKey, res.ID)
+		if res.TraceParent.IsValid() {
+			c.Header(goctxid.TraceParentHeader, res.TraceParent.String())
+		}
+		if res.RequestID != "" && cfg.RequestIDHeaderKey != "" {
+			c.Header(cfg.RequestIDHeaderKey, res.RequestID)
+		}
 
-		// 7. Get the current request context
+		// 6. Get the current request context
 		ctx := c.Request.Context()
 
-		// 8. Create a new context with our ID
-		newCtx := goctxid.NewContext(ctx, correlationID)
+		// 7. Create a new context with our ID
+		newCtx := res.NewContext(ctx)
 
-		// 9. Set the new context back into the request
+		// 8. Set the new context back into the request
 		c.Request = c.Request.WithContext(newCtx)
 
-		// 10. Continue to the next handler
+		// 9. Continue to the next handler
 		c.Next()
 	}
 }
@@ -82,14 +97,13 @@ func New(config ...Config) gin.HandlerFunc {
 //
 // ⚠️ GOROUTINE SAFETY WARNING:
 // Do NOT pass *gin.Context directly into goroutines! Gin reuses context objects.
-// Instead, pass the context.Context or copy the correlation ID value:
+// Instead, use goctxid.Go (or goctxid.NewGroup / goctxid.NewPool) or copy the ID:
 //
-//	// ✅ CORRECT - Option 1: Pass context.Context
-//	ctx := c.Request.Context()
-//	go func(ctx context.Context) {
+//	// ✅ CORRECT - Option 1: goctxid.Go (the context outlives the request)
+//	goctxid.Go(c.Request.Context(), func(ctx context.Context) {
 //	    id := MustFromContext(ctx)
 //	    log.Printf("ID: %s", id)
-//	}(ctx)
+//	})
 //
 //	// ✅ CORRECT - Option 2: Copy the value
 //	correlationID := GetCorrelationID(c)
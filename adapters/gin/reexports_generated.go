@@ -12,6 +12,13 @@ import (
 const (
 	// DefaultHeaderKey is the default HTTP header key for correlation ID
 	DefaultHeaderKey = goctxid.DefaultHeaderKey
+
+	// OriginUnknown, OriginHeader, OriginGenerated and OriginTraceParent tell
+	// where a correlation ID came from (see goctxid.IDInfo)
+	OriginUnknown     = goctxid.OriginUnknown
+	OriginHeader      = goctxid.OriginHeader
+	OriginGenerated   = goctxid.OriginGenerated
+	OriginTraceParent = goctxid.OriginTraceParent
 )
 
 // Re-exported generator functions from goctxid package for convenience
@@ -19,9 +26,24 @@ var (
 	// DefaultGenerator is the default UUID v4 generator (cryptographically secure)
 	DefaultGenerator = goctxid.DefaultGenerator
 
+	// BufferedGenerator is a UUID v4 generator that reads crypto/rand in batches
+	BufferedGenerator = goctxid.BufferedGenerator
+
 	// FastGenerator is a high-performance generator using atomic counter
 	// ⚠️ WARNING: Exposes request count. Use only when performance is critical.
 	FastGenerator = goctxid.FastGenerator
+
+	// AppendFastID appends a FastGenerator ID to a buffer without allocating
+	AppendFastID = goctxid.AppendFastID
+
+	// FastOpaqueGenerator is a high-performance generator that hides its counter
+	FastOpaqueGenerator = goctxid.FastOpaqueGenerator
+
+	// UUIDv7Generator is a time-ordered UUID v7 generator (index-friendly)
+	UUIDv7Generator = goctxid.UUIDv7Generator
+
+	// ULIDGenerator is a lexicographically sortable ULID generator (26 characters)
+	ULIDGenerator = goctxid.ULIDGenerator
 )
 
 // Re-exported functions from goctxid package for convenience
@@ -39,7 +61,31 @@ func MustFromContext(ctx context.Context) string {
 	return goctxid.MustFromContext(ctx)
 }
 
+// FromContextWithInfo retrieves the correlation ID from the context together
+// with where it came from (inbound header, generated or traceparent).
+func FromContextWithInfo(ctx context.Context) (goctxid.IDInfo, bool) {
+	return goctxid.FromContextWithInfo(ctx)
+}
+
 // NewContext creates a new context with the correlation ID.
 func NewContext(ctx context.Context, correlationID string) context.Context {
 	return goctxid.NewContext(ctx, correlationID)
 }
+
+// RequestIDFromContext retrieves the per-service request ID from the context.
+// Only set when goctxid.Config.RequestIDGenerator is configured.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	return goctxid.RequestIDFromContext(ctx)
+}
+
+// MustRequestIDFromContext retrieves the per-service request ID from the context.
+// Returns the request ID or an empty string if not found.
+func MustRequestIDFromContext(ctx context.Context) string {
+	return goctxid.MustRequestIDFromContext(ctx)
+}
+
+// IsInvalid reports whether the correlation ID in the context failed validation
+// and was kept because of goctxid.InvalidKeep.
+func IsInvalid(ctx context.Context) bool {
+	return goctxid.IsInvalid(ctx)
+}
@@ -0,0 +1,213 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hiiamtin/goctxid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Config extends goctxid.Config with gRPC-specific options
+type Config struct {
+	goctxid.Config
+
+	// Next defines a function to skip this interceptor when returned true.
+	// fullMethod is the full RPC method string, i.e., /package.service/method.
+	// Used by both server and client interceptors.
+	//
+	// Optional. Default: nil
+	Next func(ctx context.Context, fullMethod string) bool
+}
+
+// configDefault is a helper function that merges the provided config with the default config
+func configDefault(config ...Config) Config {
+
+	var cfg Config
+
+	// If a config is provided, use it
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Check and fill in default values
+	if cfg.HeaderKey == "" {
+		cfg.HeaderKey = goctxid.DefaultHeaderKey
+	}
+	// gRPC metadata keys are always lowercase
+	cfg.HeaderKey = strings.ToLower(cfg.HeaderKey)
+	// Generator must be thread-safe as interceptors run concurrently for multiple RPCs
+	if cfg.Generator == nil {
+		cfg.Generator = goctxid.DefaultGenerator
+	}
+
+	return cfg
+}
+
+// resolve extracts the correlation ID from the incoming metadata (or generates one),
+// sends it back as a response header and returns a context carrying it
+func resolve(ctx context.Context, cfg Config, fullMethod string, setHeader func(metadata.MD) error) (context.Context, error) {
+	// 1. Extract the correlation ID from the incoming metadata,
+	// validating it and generating a new one if needed
+	md, _ := metadata.FromIncomingContext(ctx)
+	info := goctxid.RequestInfo{
+		Method: "POST",
+		Path:   fullMethod,
+		Header: func(key string) string {
+			if values := md.Get(key); len(values) > 0 {
+				return values[0]
+			}
+			return ""
+		},
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		info.RemoteAddr = p.Addr.String()
+		info.RemoteIP = goctxid.ParseRemoteIP(info.RemoteAddr)
+	}
+	res, err := cfg.ResolveRequest(ctx, info)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// 2. Set the response header (send back to the client)
+	header := metadata.Pairs(cfg.HeaderKey, res.ID)
+	if res.TraceParent.IsValid() {
+		header.Set(goctxid.TraceParentHeader, res.TraceParent.String())
+	}
+	if res.RequestID != "" && cfg.RequestIDHeaderKey != "" {
+		header.Set(cfg.RequestIDHeaderKey, res.RequestID)
+	}
+	_ = setHeader(header)
+
+	// 3. Create a new context with our ID
+	return res.NewContext(ctx), nil
+}
+
+// UnaryServerInterceptor creates a gRPC unary server interceptor for correlation ID management
+func UnaryServerInterceptor(config ...Config) grpc.UnaryServerInterceptor {
+
+	// Merge the provided config with the default config
+	cfg := configDefault(config...)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		// Check if we should skip this interceptor
+		if cfg.Next != nil && cfg.Next(ctx, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		newCtx, err := resolve(ctx, cfg, info.FullMethod, func(md metadata.MD) error {
+			return grpc.SetHeader(ctx, md)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Continue to the handler with the new context
+		return handler(newCtx, req)
+	}
+}
+
+// StreamServerInterceptor creates a gRPC stream server interceptor for correlation ID management
+func StreamServerInterceptor(config ...Config) grpc.StreamServerInterceptor {
+
+	// Merge the provided config with the default config
+	cfg := configDefault(config...)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+
+		// Check if we should skip this interceptor
+		if cfg.Next != nil && cfg.Next(ctx, info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		newCtx, err := resolve(ctx, cfg, info.FullMethod, ss.SetHeader)
+		if err != nil {
+			return err
+		}
+
+		// Continue to the handler with a stream that exposes the new context
+		return handler(srv, &serverStream{ServerStream: ss, ctx: newCtx})
+	}
+}
+
+// serverStream wraps grpc.ServerStream to override its context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context carrying the correlation ID
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// outgoingContext returns ctx with the correlation ID from ctx set in the outgoing
+// metadata, replacing any value already set for cfg.HeaderKey.
+// ctx is returned unchanged when it does not carry a correlation ID.
+//
+// With EmitTraceParent, a traceparent (see goctxid.OutgoingTraceParent) is also
+// set unless the outgoing metadata already has one.
+func outgoingContext(ctx context.Context, cfg Config) context.Context {
+	id, ok := goctxid.FromContext(ctx)
+	if !ok || id == "" {
+		return ctx
+	}
+
+	// FromOutgoingContext returns a copy, so it is safe to modify
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		md = metadata.MD{}
+	}
+	md.Set(cfg.HeaderKey, id)
+	if cfg.EmitTraceParent && len(md.Get(goctxid.TraceParentHeader)) == 0 {
+		tp, _ := goctxid.OutgoingTraceParent(ctx)
+		md.Set(goctxid.TraceParentHeader, tp.String())
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// UnaryClientInterceptor creates a gRPC unary client interceptor that forwards
+// the correlation ID from the context (see goctxid.FromContext) in the outgoing metadata.
+//
+// Example usage:
+//
+//	conn, err := grpc.NewClient(target,
+//	    grpc.WithUnaryInterceptor(goctxid_grpc.UnaryClientInterceptor()),
+//	    grpc.WithStreamInterceptor(goctxid_grpc.StreamClientInterceptor()),
+//	)
+func UnaryClientInterceptor(config ...Config) grpc.UnaryClientInterceptor {
+
+	// Merge the provided config with the default config
+	cfg := configDefault(config...)
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		// Check if we should skip this interceptor
+		if cfg.Next != nil && cfg.Next(ctx, method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		return invoker(outgoingContext(ctx, cfg), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor creates a gRPC stream client interceptor that forwards
+// the correlation ID from the context (see goctxid.FromContext) in the outgoing metadata.
+func StreamClientInterceptor(config ...Config) grpc.StreamClientInterceptor {
+
+	// Merge the provided config with the default config
+	cfg := configDefault(config...)
+
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		// Check if we should skip this interceptor
+		if cfg.Next != nil && cfg.Next(ctx, method) {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+
+		return streamer(outgoingContext(ctx, cfg), desc, cc, method, opts...)
+	}
+}
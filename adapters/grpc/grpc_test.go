@@ -0,0 +1,1124 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/hiiamtin/goctxid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testHealthServer is a health service that reports the context of each RPC
+// (Check is unary, Watch is server streaming)
+// testTraceParent is a fixed traceparent used by client interceptor tests
+var testTraceParent, _ = goctxid.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+type testHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	onCall func(ctx context.Context)
+}
+
+func (s *testHealthServer) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	s.onCall(ctx)
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *testHealthServer) Watch(_ *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	s.onCall(stream.Context())
+	return stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING})
+}
+
+// newTestClient starts an in-memory gRPC server with the given options and
+// returns a health client connected to it
+func newTestClient(t testing.TB, onCall func(ctx context.Context), opts ...grpc.ServerOption) grpc_health_v1.HealthClient {
+	t.Helper()
+	return newTestClientWithDialOptions(t, onCall, nil, opts...)
+}
+
+// newTestClientWithDialOptions is like newTestClient but also applies client dial options
+func newTestClientWithDialOptions(t testing.TB, onCall func(ctx context.Context), dialOpts []grpc.DialOption, opts ...grpc.ServerOption) grpc_health_v1.HealthClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(opts...)
+	grpc_health_v1.RegisterHealthServer(server, &testHealthServer{onCall: onCall})
+	go func() { _ = server.Serve(lis) }()
+
+	dialOpts = append(dialOpts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.NewClient("passthrough:///bufnet", dialOpts...)
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		server.Stop()
+	})
+
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+// callUnary performs a unary RPC and returns the response header
+func callUnary(ctx context.Context, client grpc_health_v1.HealthClient) (metadata.MD, error) {
+	var header metadata.MD
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{}, grpc.Header(&header))
+	return header, err
+}
+
+// callStream performs a server-streaming RPC and returns the response header
+func callStream(ctx context.Context, client grpc_health_v1.HealthClient) (metadata.MD, error) {
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+	}
+	header, _ := stream.Header()
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	return header, err
+}
+
+func TestInterceptors(t *testing.T) {
+	tests := []struct {
+		name               string
+		config             []Config
+		requestHeader      string
+		requestHeaderValue string
+		expectedInContext  string
+		checkResponseKey   string
+	}{
+		{
+			name:              "generates new ID when metadata not present",
+			config:            nil,
+			expectedInContext: "", // Will be generated, just check it exists
+			checkResponseKey:  "x-correlation-id",
+		},
+		{
+			name:               "uses existing ID from request metadata",
+			config:             nil,
+			requestHeader:      goctxid.DefaultHeaderKey,
+			requestHeaderValue: "existing-correlation-id",
+			expectedInContext:  "existing-correlation-id",
+			checkResponseKey:   "x-correlation-id",
+		},
+		{
+			name: "uses custom header key (lowercased)",
+			config: []Config{
+				{
+					Config: goctxid.Config{
+						HeaderKey: "X-Custom-ID",
+					},
+				},
+			},
+			requestHeader:      "x-custom-id",
+			requestHeaderValue: "custom-id-123",
+			expectedInContext:  "custom-id-123",
+			checkResponseKey:   "x-custom-id",
+		},
+		{
+			name: "uses custom generator",
+			config: []Config{
+				{
+					Config: goctxid.Config{
+						Generator: func() string {
+							return "custom-generated-id"
+						},
+					},
+				},
+			},
+			expectedInContext: "custom-generated-id",
+			checkResponseKey:  "x-correlation-id",
+		},
+	}
+
+	calls := map[string]func(context.Context, grpc_health_v1.HealthClient) (metadata.MD, error){
+		"unary":  callUnary,
+		"stream": callStream,
+	}
+
+	for _, tt := range tests {
+		for kind, call := range calls {
+			t.Run(kind+"/"+tt.name, func(t *testing.T) {
+				var contextID string
+				onCall := func(ctx context.Context) {
+					id, exists := goctxid.FromContext(ctx)
+					if !exists {
+						t.Error("Correlation ID not found in context")
+					}
+					contextID = id
+				}
+
+				client := newTestClient(t, onCall,
+					grpc.UnaryInterceptor(UnaryServerInterceptor(tt.config...)),
+					grpc.StreamInterceptor(StreamServerInterceptor(tt.config...)),
+				)
+
+				ctx := context.Background()
+				if tt.requestHeader != "" {
+					ctx = metadata.AppendToOutgoingContext(ctx, tt.requestHeader, tt.requestHeaderValue)
+				}
+
+				header, err := call(ctx, client)
+				if err != nil {
+					t.Fatalf("RPC failed: %v", err)
+				}
+
+				// Check response header
+				values := header.Get(tt.checkResponseKey)
+				if len(values) != 1 {
+					t.Fatalf("Response header %q = %v, want exactly one value", tt.checkResponseKey, values)
+				}
+				responseID := values[0]
+
+				// Check expected values
+				if tt.expectedInContext != "" {
+					if contextID != tt.expectedInContext {
+						t.Errorf("Context ID = %v, want %v", contextID, tt.expectedInContext)
+					}
+				} else if contextID == "" {
+					t.Error("Context ID is empty")
+				}
+
+				// Verify context and response have same ID
+				if contextID != responseID {
+					t.Errorf("Context ID (%v) != Response ID (%v)", contextID, responseID)
+				}
+			})
+		}
+	}
+}
+
+func TestConfigDefault(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            []Config
+		expectedHeaderKey string
+		testGenerator     bool
+	}{
+		{
+			name:              "uses defaults when no config provided",
+			config:            nil,
+			expectedHeaderKey: "x-correlation-id",
+			testGenerator:     true,
+		},
+		{
+			name:              "uses defaults when empty config provided",
+			config:            []Config{{}},
+			expectedHeaderKey: "x-correlation-id",
+			testGenerator:     true,
+		},
+		{
+			name: "lowercases custom header key",
+			config: []Config{
+				{Config: goctxid.Config{HeaderKey: "X-Request-ID"}},
+			},
+			expectedHeaderKey: "x-request-id",
+			testGenerator:     true,
+		},
+		{
+			name: "uses custom generator",
+			config: []Config{
+				{
+					Config: goctxid.Config{
+						Generator: func() string { return "test" },
+					},
+				},
+			},
+			expectedHeaderKey: "x-correlation-id",
+			testGenerator:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := configDefault(tt.config...)
+
+			if cfg.HeaderKey != tt.expectedHeaderKey {
+				t.Errorf("HeaderKey = %v, want %v", cfg.HeaderKey, tt.expectedHeaderKey)
+			}
+
+			if cfg.Generator == nil {
+				t.Error("Generator is nil")
+			}
+
+			if tt.testGenerator {
+				// Test that default generator works
+				id := cfg.Generator()
+				if id == "" {
+					t.Error("Generator returned empty string")
+				}
+			}
+		})
+	}
+}
+
+// TestValidator tests how invalid inbound IDs are handled by each policy
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          goctxid.InvalidPolicy
+		inbound         string
+		expectedCode    codes.Code
+		expectedID      string
+		expectedInvalid bool
+	}{
+		{
+			name:         "accepts valid ID",
+			policy:       goctxid.InvalidReject,
+			inbound:      "123e4567-e89b-12d3-a456-426614174000",
+			expectedCode: codes.OK,
+			expectedID:   "123e4567-e89b-12d3-a456-426614174000",
+		},
+		{
+			name:         "regenerates invalid ID",
+			policy:       goctxid.InvalidRegenerate,
+			inbound:      "not-a-uuid",
+			expectedCode: codes.OK,
+			expectedID:   "generated-id",
+		},
+		{
+			name:         "rejects invalid ID",
+			policy:       goctxid.InvalidReject,
+			inbound:      "not-a-uuid",
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:            "keeps and flags invalid ID",
+			policy:          goctxid.InvalidKeep,
+			inbound:         "not-a-uuid",
+			expectedCode:    codes.OK,
+			expectedID:      "not-a-uuid",
+			expectedInvalid: true,
+		},
+	}
+
+	calls := map[string]func(context.Context, grpc_health_v1.HealthClient) (metadata.MD, error){
+		"unary":  callUnary,
+		"stream": callStream,
+	}
+
+	for _, tt := range tests {
+		for kind, call := range calls {
+			t.Run(kind+"/"+tt.name, func(t *testing.T) {
+				cfg := Config{
+					Config: goctxid.Config{
+						Generator: func() string { return "generated-id" },
+						Validator: goctxid.ValidateUUID,
+						OnInvalid: tt.policy,
+					},
+				}
+
+				var contextID string
+				var invalid bool
+				client := newTestClient(t, func(ctx context.Context) {
+					contextID = MustFromContext(ctx)
+					invalid = IsInvalid(ctx)
+				},
+					grpc.UnaryInterceptor(UnaryServerInterceptor(cfg)),
+					grpc.StreamInterceptor(StreamServerInterceptor(cfg)),
+				)
+
+				ctx := metadata.AppendToOutgoingContext(context.Background(), DefaultHeaderKey, tt.inbound)
+				_, err := call(ctx, client)
+
+				if code := status.Code(err); code != tt.expectedCode {
+					t.Fatalf("Expected code %v, got %v (%v)", tt.expectedCode, code, err)
+				}
+				if contextID != tt.expectedID {
+					t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+				}
+				if invalid != tt.expectedInvalid {
+					t.Errorf("IsInvalid = %v, want %v", invalid, tt.expectedInvalid)
+				}
+			})
+		}
+	}
+}
+
+func TestTraceParent(t *testing.T) {
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	tests := []struct {
+		name               string
+		config             goctxid.Config
+		inboundTraceParent string
+		expectedID         string
+		expectedEmit       bool
+	}{
+		{
+			name:               "ignores traceparent by default",
+			config:             goctxid.Config{},
+			inboundTraceParent: traceParent,
+			expectedID:         "generated-id",
+		},
+		{
+			name:               "derives ID from traceparent",
+			config:             goctxid.Config{UseTraceParent: true},
+			inboundTraceParent: traceParent,
+			expectedID:         traceID,
+		},
+		{
+			name:               "continues inbound trace",
+			config:             goctxid.Config{UseTraceParent: true, EmitTraceParent: true},
+			inboundTraceParent: traceParent,
+			expectedID:         traceID,
+			expectedEmit:       true,
+		},
+		{
+			name:         "starts new trace",
+			config:       goctxid.Config{EmitTraceParent: true},
+			expectedID:   "generated-id",
+			expectedEmit: true,
+		},
+	}
+
+	calls := map[string]func(context.Context, grpc_health_v1.HealthClient) (metadata.MD, error){
+		"unary":  callUnary,
+		"stream": callStream,
+	}
+
+	for _, tt := range tests {
+		for kind, call := range calls {
+			t.Run(kind+"/"+tt.name, func(t *testing.T) {
+				cfg := Config{Config: tt.config}
+				cfg.Generator = func() string { return "generated-id" }
+
+				var contextID string
+				var contextTraceParent goctxid.TraceParent
+				var ok bool
+				client := newTestClient(t, func(ctx context.Context) {
+					contextID = MustFromContext(ctx)
+					contextTraceParent, ok = goctxid.TraceParentFromContext(ctx)
+				},
+					grpc.UnaryInterceptor(UnaryServerInterceptor(cfg)),
+					grpc.StreamInterceptor(StreamServerInterceptor(cfg)),
+				)
+
+				ctx := context.Background()
+				if tt.inboundTraceParent != "" {
+					ctx = metadata.AppendToOutgoingContext(ctx, goctxid.TraceParentHeader, tt.inboundTraceParent)
+				}
+				header, err := call(ctx, client)
+				if err != nil {
+					t.Fatalf("RPC failed: %v", err)
+				}
+
+				if contextID != tt.expectedID {
+					t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+				}
+				if ok != tt.expectedEmit {
+					t.Fatalf("Traceparent in context = %v, want %v", ok, tt.expectedEmit)
+				}
+
+				responseTraceParent := header.Get(goctxid.TraceParentHeader)
+				if !tt.expectedEmit {
+					if len(responseTraceParent) != 0 {
+						t.Errorf("Unexpected traceparent response header %v", responseTraceParent)
+					}
+					return
+				}
+				if len(responseTraceParent) != 1 || responseTraceParent[0] != contextTraceParent.String() {
+					t.Errorf("Response traceparent = %v, want %v", responseTraceParent, contextTraceParent.String())
+				}
+				if tt.inboundTraceParent != "" && contextTraceParent.TraceIDString() != traceID {
+					t.Errorf("Trace ID = %v, want %v", contextTraceParent.TraceIDString(), traceID)
+				}
+			})
+		}
+	}
+}
+
+func TestInboundHeaderKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		metadata   []string
+		expectedID string
+	}{
+		{
+			name:       "uses first listed key",
+			metadata:   []string{DefaultHeaderKey, "correlation-id", "x-request-id", "request-id"},
+			expectedID: "correlation-id",
+		},
+		{
+			name:       "falls back to later key",
+			metadata:   []string{"x-request-id", "request-id"},
+			expectedID: "request-id",
+		},
+		{
+			name:       "generates ID without listed keys",
+			metadata:   []string{"x-other-id", "other-id"},
+			expectedID: "generated-id",
+		},
+	}
+
+	calls := map[string]func(context.Context, grpc_health_v1.HealthClient) (metadata.MD, error){
+		"unary":  callUnary,
+		"stream": callStream,
+	}
+
+	for _, tt := range tests {
+		for kind, call := range calls {
+			t.Run(kind+"/"+tt.name, func(t *testing.T) {
+				cfg := Config{
+					Config: goctxid.Config{
+						// Mixed case keys work because metadata keys are case-insensitive
+						InboundHeaderKeys: []string{"X-Correlation-ID", "X-Request-ID"},
+						Generator:         func() string { return "generated-id" },
+					},
+				}
+
+				var contextID string
+				client := newTestClient(t, func(ctx context.Context) {
+					contextID = MustFromContext(ctx)
+				},
+					grpc.UnaryInterceptor(UnaryServerInterceptor(cfg)),
+					grpc.StreamInterceptor(StreamServerInterceptor(cfg)),
+				)
+
+				ctx := metadata.AppendToOutgoingContext(context.Background(), tt.metadata...)
+				header, err := call(ctx, client)
+				if err != nil {
+					t.Fatalf("RPC failed: %v", err)
+				}
+
+				if contextID != tt.expectedID {
+					t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+				}
+				if values := header.Get(DefaultHeaderKey); len(values) != 1 || values[0] != tt.expectedID {
+					t.Errorf("Response header ID = %v, want %v", values, tt.expectedID)
+				}
+			})
+		}
+	}
+}
+
+// TestNextFunction tests the Next configuration option
+func TestNextFunction(t *testing.T) {
+	cfg := Config{
+		Next: func(ctx context.Context, fullMethod string) bool {
+			// Skip all health RPCs
+			return fullMethod == grpc_health_v1.Health_Check_FullMethodName ||
+				fullMethod == grpc_health_v1.Health_Watch_FullMethodName
+		},
+	}
+
+	calls := map[string]func(context.Context, grpc_health_v1.HealthClient) (metadata.MD, error){
+		"unary":  callUnary,
+		"stream": callStream,
+	}
+
+	for kind, call := range calls {
+		t.Run(kind, func(t *testing.T) {
+			var exists bool
+			client := newTestClient(t, func(ctx context.Context) {
+				_, exists = FromContext(ctx)
+			},
+				grpc.UnaryInterceptor(UnaryServerInterceptor(cfg)),
+				grpc.StreamInterceptor(StreamServerInterceptor(cfg)),
+			)
+
+			header, err := call(context.Background(), client)
+			if err != nil {
+				t.Fatalf("RPC failed: %v", err)
+			}
+
+			// This should NOT have a correlation ID because the interceptor was skipped
+			if exists {
+				t.Error("Expected no correlation ID in context for skipped method")
+			}
+			if values := header.Get(DefaultHeaderKey); len(values) != 0 {
+				t.Errorf("Expected no correlation ID header for skipped method, got %v", values)
+			}
+		})
+	}
+}
+
+func TestConcurrentRPCs(t *testing.T) {
+	var mu sync.Mutex
+	seenIDs := make(map[string]bool)
+
+	client := newTestClient(t, func(ctx context.Context) {
+		id, exists := goctxid.FromContext(ctx)
+		if !exists {
+			t.Error("Correlation ID not found in context")
+		}
+
+		mu.Lock()
+		seenIDs[id] = true
+		mu.Unlock()
+	}, grpc.UnaryInterceptor(UnaryServerInterceptor()))
+
+	// Make multiple concurrent RPCs
+	var wg sync.WaitGroup
+	numRequests := 50
+
+	for i := 0; i < numRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := callUnary(context.Background(), client); err != nil {
+				t.Errorf("RPC failed: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Verify we got unique IDs for each RPC
+	mu.Lock()
+	uniqueCount := len(seenIDs)
+	mu.Unlock()
+
+	if uniqueCount != numRequests {
+		t.Errorf("Expected %d unique IDs, got %d", numRequests, uniqueCount)
+	}
+}
+
+func TestGeneratorThreadSafety(t *testing.T) {
+	// Test that custom generator is called safely from multiple goroutines
+	var callCount int
+	var mu sync.Mutex
+
+	generator := func() string {
+		mu.Lock()
+		callCount++
+		mu.Unlock()
+		return uuid.NewString() // Use a different ID for each call
+	}
+
+	client := newTestClient(t, func(context.Context) {},
+		grpc.UnaryInterceptor(UnaryServerInterceptor(Config{Config: goctxid.Config{Generator: generator}})))
+
+	var wg sync.WaitGroup
+	numRequests := 20
+
+	for i := 0; i < numRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = callUnary(context.Background(), client)
+		}()
+	}
+
+	wg.Wait()
+
+	mu.Lock()
+	finalCount := callCount
+	mu.Unlock()
+
+	if finalCount != numRequests {
+		t.Errorf("Generator called %d times, expected %d", finalCount, numRequests)
+	}
+}
+
+// TestReExportedFunctions tests that re-exported functions work correctly
+// This allows users to use goctxid_grpc.FromContext() instead of importing goctxid
+func TestReExportedFunctions(t *testing.T) {
+	ctx := context.Background()
+
+	// Test NewContext
+	testID := "test-correlation-id-123"
+	newCtx := NewContext(ctx, testID)
+
+	// Test FromContext
+	retrievedID, exists := FromContext(newCtx)
+	if !exists {
+		t.Error("FromContext should return true for existing ID")
+	}
+	if retrievedID != testID {
+		t.Errorf("FromContext returned wrong ID: got %s, want %s", retrievedID, testID)
+	}
+
+	// Test MustFromContext
+	if mustID := MustFromContext(newCtx); mustID != testID {
+		t.Errorf("MustFromContext returned wrong ID: got %s, want %s", mustID, testID)
+	}
+
+	// Test re-exported constants
+	if DefaultHeaderKey != "X-Correlation-ID" {
+		t.Errorf("DefaultHeaderKey should be X-Correlation-ID, got %s", DefaultHeaderKey)
+	}
+
+	// Test that generators actually work
+	if DefaultGenerator() == "" {
+		t.Error("DefaultGenerator should return non-empty ID")
+	}
+	if FastGenerator() == "" {
+		t.Error("FastGenerator should return non-empty ID")
+	}
+	if UUIDv7Generator() == "" {
+		t.Error("UUIDv7Generator should return non-empty ID")
+	}
+	if ULIDGenerator() == "" {
+		t.Error("ULIDGenerator should return non-empty ID")
+	}
+	if FastOpaqueGenerator() == "" {
+		t.Error("FastOpaqueGenerator should return non-empty ID")
+	}
+	if BufferedGenerator() == "" {
+		t.Error("BufferedGenerator should return non-empty ID")
+	}
+}
+
+// TestClientInterceptors tests that the correlation ID from the context is
+// forwarded to the server in the outgoing metadata
+func TestClientInterceptors(t *testing.T) {
+	tests := []struct {
+		name             string
+		config           []Config
+		ctx              func() context.Context
+		expectedMetadata []string
+		checkKey         string
+	}{
+		{
+			name: "forwards ID from context",
+			ctx: func() context.Context {
+				return NewContext(context.Background(), "client-id")
+			},
+			expectedMetadata: []string{"client-id"},
+			checkKey:         "x-correlation-id",
+		},
+		{
+			name: "uses custom header key (lowercased)",
+			config: []Config{
+				{Config: goctxid.Config{HeaderKey: "X-Request-ID"}},
+			},
+			ctx: func() context.Context {
+				return NewContext(context.Background(), "client-id")
+			},
+			expectedMetadata: []string{"client-id"},
+			checkKey:         "x-request-id",
+		},
+		{
+			name: "replaces existing outgoing metadata value",
+			ctx: func() context.Context {
+				ctx := metadata.AppendToOutgoingContext(context.Background(),
+					"x-correlation-id", "stale-id", "x-other", "kept")
+				return NewContext(ctx, "client-id")
+			},
+			expectedMetadata: []string{"client-id"},
+			checkKey:         "x-correlation-id",
+		},
+		{
+			name: "keeps other outgoing metadata",
+			ctx: func() context.Context {
+				ctx := metadata.AppendToOutgoingContext(context.Background(), "x-other", "kept")
+				return NewContext(ctx, "client-id")
+			},
+			expectedMetadata: []string{"kept"},
+			checkKey:         "x-other",
+		},
+		{
+			name: "sends nothing without ID in context",
+			ctx: func() context.Context {
+				return context.Background()
+			},
+			expectedMetadata: nil,
+			checkKey:         "x-correlation-id",
+		},
+		{
+			name: "sends nothing with empty ID in context",
+			ctx: func() context.Context {
+				return NewContext(context.Background(), "")
+			},
+			expectedMetadata: nil,
+			checkKey:         "x-correlation-id",
+		},
+		{
+			name: "skips methods matched by Next",
+			config: []Config{
+				{
+					Next: func(ctx context.Context, fullMethod string) bool {
+						return true
+					},
+				},
+			},
+			ctx: func() context.Context {
+				return NewContext(context.Background(), "client-id")
+			},
+			expectedMetadata: nil,
+			checkKey:         "x-correlation-id",
+		},
+		{
+			name: "does not send traceparent by default",
+			ctx: func() context.Context {
+				return goctxid.NewTraceParentContext(NewContext(context.Background(), "client-id"), testTraceParent)
+			},
+			expectedMetadata: nil,
+			checkKey:         goctxid.TraceParentHeader,
+		},
+		{
+			name: "sends traceparent from context with EmitTraceParent",
+			config: []Config{
+				{Config: goctxid.Config{EmitTraceParent: true}},
+			},
+			ctx: func() context.Context {
+				return goctxid.NewTraceParentContext(NewContext(context.Background(), "client-id"), testTraceParent)
+			},
+			expectedMetadata: []string{testTraceParent.String()},
+			checkKey:         goctxid.TraceParentHeader,
+		},
+		{
+			name: "keeps existing outgoing traceparent",
+			config: []Config{
+				{Config: goctxid.Config{EmitTraceParent: true}},
+			},
+			ctx: func() context.Context {
+				ctx := metadata.AppendToOutgoingContext(context.Background(),
+					goctxid.TraceParentHeader, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+				return goctxid.NewTraceParentContext(NewContext(ctx, "client-id"), testTraceParent)
+			},
+			expectedMetadata: []string{"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+			checkKey:         goctxid.TraceParentHeader,
+		},
+	}
+
+	calls := map[string]func(context.Context, grpc_health_v1.HealthClient) (metadata.MD, error){
+		"unary":  callUnary,
+		"stream": callStream,
+	}
+
+	for _, tt := range tests {
+		for kind, call := range calls {
+			t.Run(kind+"/"+tt.name, func(t *testing.T) {
+				var received []string
+				client := newTestClientWithDialOptions(t, func(ctx context.Context) {
+					md, _ := metadata.FromIncomingContext(ctx)
+					received = md.Get(tt.checkKey)
+				}, []grpc.DialOption{
+					grpc.WithUnaryInterceptor(UnaryClientInterceptor(tt.config...)),
+					grpc.WithStreamInterceptor(StreamClientInterceptor(tt.config...)),
+				})
+
+				if _, err := call(tt.ctx(), client); err != nil {
+					t.Fatalf("RPC failed: %v", err)
+				}
+
+				if len(received) != len(tt.expectedMetadata) {
+					t.Fatalf("Server received %v, want %v", received, tt.expectedMetadata)
+				}
+				for i := range received {
+					if received[i] != tt.expectedMetadata[i] {
+						t.Errorf("Server received %v, want %v", received, tt.expectedMetadata)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestEndToEndPropagation tests that an ID flows from a client context through
+// the client interceptor into the server context
+func TestEndToEndPropagation(t *testing.T) {
+	var serverID string
+	client := newTestClientWithDialOptions(t, func(ctx context.Context) {
+		serverID = MustFromContext(ctx)
+	}, []grpc.DialOption{
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+	}, grpc.UnaryInterceptor(UnaryServerInterceptor()))
+
+	// Simulates an HTTP handler context populated by another adapter
+	ctx := NewContext(context.Background(), "http-request-id")
+
+	header, err := callUnary(ctx, client)
+	if err != nil {
+		t.Fatalf("RPC failed: %v", err)
+	}
+
+	if serverID != "http-request-id" {
+		t.Errorf("Server context ID = %v, want %v", serverID, "http-request-id")
+	}
+	if values := header.Get(DefaultHeaderKey); len(values) != 1 || values[0] != "http-request-id" {
+		t.Errorf("Response header = %v, want [%v]", values, "http-request-id")
+	}
+}
+
+func BenchmarkUnaryClientInterceptor(b *testing.B) {
+	interceptor := UnaryClientInterceptor()
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	ctx := NewContext(context.Background(), "benchmark-id")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = interceptor(ctx, grpc_health_v1.Health_Check_FullMethodName, nil, nil, nil, invoker)
+	}
+}
+
+func BenchmarkUnaryServerInterceptor(b *testing.B) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: grpc_health_v1.Health_Check_FullMethodName}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-correlation-id", "existing-id-123"))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = interceptor(ctx, nil, info, handler)
+	}
+}
+
+// TestRequestGenerator tests that RequestGenerator sees the RPC
+func TestRequestGenerator(t *testing.T) {
+	calls := map[string]struct {
+		call       func(context.Context, grpc_health_v1.HealthClient) (metadata.MD, error)
+		fullMethod string
+	}{
+		"unary":  {callUnary, grpc_health_v1.Health_Check_FullMethodName},
+		"stream": {callStream, grpc_health_v1.Health_Watch_FullMethodName},
+	}
+
+	for kind, tc := range calls {
+		t.Run(kind, func(t *testing.T) {
+			var mu sync.Mutex
+			var got goctxid.RequestInfo
+			cfg := Config{
+				Config: goctxid.Config{
+					RequestGenerator: func(ctx context.Context, r goctxid.RequestInfo) string {
+						mu.Lock()
+						defer mu.Unlock()
+						got = r
+						return r.Header("x-tenant") + "-generated"
+					},
+				},
+			}
+
+			var contextID string
+			client := newTestClient(t, func(ctx context.Context) {
+				contextID = MustFromContext(ctx)
+			},
+				grpc.UnaryInterceptor(UnaryServerInterceptor(cfg)),
+				grpc.StreamInterceptor(StreamServerInterceptor(cfg)),
+			)
+
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "x-tenant", "acme")
+			header, err := tc.call(ctx, client)
+			if err != nil {
+				t.Fatalf("RPC failed: %v", err)
+			}
+
+			if contextID != "acme-generated" {
+				t.Errorf("Context ID = %v, want %v", contextID, "acme-generated")
+			}
+			if values := header.Get(DefaultHeaderKey); len(values) != 1 || values[0] != "acme-generated" {
+				t.Errorf("Response header ID = %v, want %v", values, "acme-generated")
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if got.Method != "POST" || got.Path != tc.fullMethod {
+				t.Errorf("RequestInfo = %s %s, want POST %s", got.Method, got.Path, tc.fullMethod)
+			}
+			if got.RemoteAddr == "" {
+				t.Error("RequestInfo.RemoteAddr is empty")
+			}
+		})
+	}
+}
+
+// TestFromContextWithInfo tests that the origin of the correlation ID is recorded
+func TestFromContextWithInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		metadata     []string
+		expectedInfo goctxid.IDInfo
+	}{
+		{
+			name:         "generated",
+			metadata:     nil,
+			expectedInfo: goctxid.IDInfo{ID: "generated-id", Origin: OriginGenerated},
+		},
+		{
+			name:         "from inbound metadata",
+			metadata:     []string{"x-request-id", "request-id"},
+			expectedInfo: goctxid.IDInfo{ID: "request-id", Origin: OriginHeader, HeaderKey: "x-request-id"},
+		},
+	}
+
+	calls := map[string]func(context.Context, grpc_health_v1.HealthClient) (metadata.MD, error){
+		"unary":  callUnary,
+		"stream": callStream,
+	}
+
+	for _, tt := range tests {
+		for kind, call := range calls {
+			t.Run(kind+"/"+tt.name, func(t *testing.T) {
+				cfg := Config{
+					Config: goctxid.Config{
+						InboundHeaderKeys: []string{"x-correlation-id", "x-request-id"},
+						Generator:         func() string { return "generated-id" },
+					},
+				}
+
+				var info goctxid.IDInfo
+				client := newTestClient(t, func(ctx context.Context) {
+					info, _ = FromContextWithInfo(ctx)
+				},
+					grpc.UnaryInterceptor(UnaryServerInterceptor(cfg)),
+					grpc.StreamInterceptor(StreamServerInterceptor(cfg)),
+				)
+
+				ctx := metadata.AppendToOutgoingContext(context.Background(), tt.metadata...)
+				if _, err := call(ctx, client); err != nil {
+					t.Fatalf("RPC failed: %v", err)
+				}
+
+				if info != tt.expectedInfo {
+					t.Errorf("FromContextWithInfo() = %+v, want %+v", info, tt.expectedInfo)
+				}
+			})
+		}
+	}
+}
+
+// TestRequestID tests the per-service request ID next to the upstream correlation ID
+func TestRequestID(t *testing.T) {
+	calls := map[string]func(context.Context, grpc_health_v1.HealthClient) (metadata.MD, error){
+		"unary":  callUnary,
+		"stream": callStream,
+	}
+
+	for kind, call := range calls {
+		t.Run(kind, func(t *testing.T) {
+			cfg := Config{
+				Config: goctxid.Config{
+					RequestIDGenerator: func() string { return "request-id" },
+					RequestIDHeaderKey: "X-Request-ID",
+				},
+			}
+
+			var contextID, requestID string
+			client := newTestClient(t, func(ctx context.Context) {
+				contextID = MustFromContext(ctx)
+				requestID = MustRequestIDFromContext(ctx)
+			},
+				grpc.UnaryInterceptor(UnaryServerInterceptor(cfg)),
+				grpc.StreamInterceptor(StreamServerInterceptor(cfg)),
+			)
+
+			ctx := metadata.AppendToOutgoingContext(context.Background(), DefaultHeaderKey, "upstream-id")
+			header, err := call(ctx, client)
+			if err != nil {
+				t.Fatalf("RPC failed: %v", err)
+			}
+
+			if contextID != "upstream-id" {
+				t.Errorf("Context ID = %v, want %v", contextID, "upstream-id")
+			}
+			if requestID != "request-id" {
+				t.Errorf("Request ID = %v, want %v", requestID, "request-id")
+			}
+			if values := header.Get("x-request-id"); len(values) != 1 || values[0] != "request-id" {
+				t.Errorf("Response request ID = %v, want %v", values, "request-id")
+			}
+		})
+	}
+}
+
+func TestTrustInbound(t *testing.T) {
+	tests := []struct {
+		name       string
+		md         []string
+		expectedID string
+	}{
+		{name: "honors ID from trusted caller", md: []string{DefaultHeaderKey, "upstream-id", "x-internal", "yes"}, expectedID: "upstream-id"},
+		{name: "regenerates ID from untrusted caller", md: []string{DefaultHeaderKey, "upstream-id"}, expectedID: "generated-id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				Config: goctxid.Config{
+					Generator: func() string { return "generated-id" },
+					// bufconn peers have no IP address, so they never match a prefix
+					TrustedProxies: []netip.Prefix{netip.MustParsePrefix("0.0.0.0/0")},
+					TrustInbound:   func(r goctxid.RequestInfo) bool { return r.Header("x-internal") == "yes" },
+				},
+			}
+
+			var contextID string
+			client := newTestClient(t, func(ctx context.Context) {
+				contextID = MustFromContext(ctx)
+			}, grpc.UnaryInterceptor(UnaryServerInterceptor(cfg)))
+
+			ctx := metadata.AppendToOutgoingContext(context.Background(), tt.md...)
+			if _, err := callUnary(ctx, client); err != nil {
+				t.Fatalf("RPC failed: %v", err)
+			}
+
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+		})
+	}
+}
+
+func TestSigner(t *testing.T) {
+	signer, err := goctxid.NewSigner([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		inbound    string
+		expectedID string
+	}{
+		{name: "honors signed ID", inbound: signer.Sign("upstream-id"), expectedID: signer.Sign("upstream-id")},
+		{name: "regenerates forged ID", inbound: "upstream-id.AAAAAAAAAAAAAAAA", expectedID: signer.Sign("generated-id")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				Config: goctxid.Config{
+					Generator: func() string { return "generated-id" },
+					Signer:    signer,
+				},
+			}
+
+			var contextID string
+			client := newTestClient(t, func(ctx context.Context) {
+				contextID = MustFromContext(ctx)
+			}, grpc.UnaryInterceptor(UnaryServerInterceptor(cfg)))
+
+			ctx := metadata.AppendToOutgoingContext(context.Background(), DefaultHeaderKey, tt.inbound)
+			header, err := callUnary(ctx, client)
+			if err != nil {
+				t.Fatalf("RPC failed: %v", err)
+			}
+
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			if values := header.Get(DefaultHeaderKey); len(values) != 1 || values[0] != tt.expectedID {
+				t.Errorf("Response header ID = %v, want %v", values, tt.expectedID)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package nethttp
+
+import (
+	"net/http"
+
+	"github.com/hiiamtin/goctxid"
+)
+
+// Config extends goctxid.Config with net/http-specific options
+type Config struct {
+	goctxid.Config
+
+	// Next defines a function to skip this middleware when returned true.
+	//
+	// Optional. Default: nil
+	Next func(r *http.Request) bool
+}
+
+// configDefault is a helper function that merges the provided config with the default config
+func configDefault(config ...Config) Config {
+
+	var cfg Config
+
+	// If a config is provided, use it
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Check and fill in default values
+	if cfg.HeaderKey == "" {
+		cfg.HeaderKey = goctxid.DefaultHeaderKey
+	}
+	// Generator must be thread-safe as middleware runs concurrently for multiple requests
+	if cfg.Generator == nil {
+		cfg.Generator = goctxid.DefaultGenerator
+	}
+
+	return cfg
+}
+
+// New creates a new net/http middleware for correlation ID management.
+// The returned function wraps any http.Handler, so it works with http.ServeMux
+// and routers built on the standard library (chi, gorilla/mux, httprouter, ...).
+func New(config ...Config) func(http.Handler) http.Handler {
+
+	// 1. Merge the provided config with the default config
+	cfg := configDefault(config...)
+
+	// 2. Return the middleware function
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// 3. Check if we should skip this middleware
+			if cfg.Next != nil && cfg.Next(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// 4. Extract the correlation ID from the request header,
+			// validating it and generating a new one if needed
+			res, err := cfg.ResolveRequest(r.Context(), goctxid.RequestInfo{
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				RemoteAddr: r.RemoteAddr,
+				RemoteIP:   goctxid.ParseRemoteIP(r.RemoteAddr),
+				Header:     r.Header.Get,
+			})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			// 5. Set the response header (send back to the client)
+			w.Header().Set(cfg.HeaderKey, res.ID)
+			if res.TraceParent.IsValid() {
+				w.Header().Set(goctxid.TraceParentHeader, res.TraceParent.String())
+			}
+			if res.RequestID != "" && cfg.RequestIDHeaderKey != "" {
+				w.Header().Set(cfg.RequestIDHeaderKey, res.RequestID)
+			}
+
+			// 6. Create a new context with our ID
+			newCtx := res.NewContext(r.Context())
+
+			// 7. Continue to the next handler with the new context
+			next.ServeHTTP(w, r.WithContext(newCtx))
+		})
+	}
+}
+
+// GetCorrelationID retrieves the correlation ID from the request.
+// Returns the correlation ID or an empty string if not found.
+// This is a convenience function equivalent to MustFromContext(r.Context()).
+//
+// Unlike framework contexts, *http.Request is not recycled, but it is still
+// simplest to use goctxid.Go (or goctxid.NewGroup / goctxid.NewPool) or copy the ID:
+//
+//	// ✅ CORRECT - Option 1: goctxid.Go (the context outlives the request)
+//	goctxid.Go(r.Context(), func(ctx context.Context) {
+//	    id := MustFromContext(ctx)
+//	    log.Printf("ID: %s", id)
+//	})
+//
+//	// ✅ CORRECT - Option 2: Copy the value
+//	correlationID := GetCorrelationID(r)
+//	go func(id string) {
+//	    log.Printf("ID: %s", id)
+//	}(correlationID)
+func GetCorrelationID(r *http.Request) string {
+	return MustFromContext(r.Context())
+}
@@ -0,0 +1,1294 @@
+package nethttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hiiamtin/goctxid"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name               string
+		config             []Config
+		requestHeader      string
+		requestHeaderValue string
+		expectedInContext  string
+		expectedInResponse string
+		checkResponseKey   string
+	}{
+		{
+			name:               "generates new ID when header not present",
+			config:             nil,
+			requestHeader:      "",
+			requestHeaderValue: "",
+			expectedInContext:  "", // Will be generated, just check it exists
+			expectedInResponse: "", // Will be generated, just check it exists
+			checkResponseKey:   goctxid.DefaultHeaderKey,
+		},
+		{
+			name:               "uses existing ID from request header",
+			config:             nil,
+			requestHeader:      goctxid.DefaultHeaderKey,
+			requestHeaderValue: "existing-correlation-id",
+			expectedInContext:  "existing-correlation-id",
+			expectedInResponse: "existing-correlation-id",
+			checkResponseKey:   goctxid.DefaultHeaderKey,
+		},
+		{
+			name: "uses custom header key",
+			config: []Config{
+				{
+					Config: goctxid.Config{
+						HeaderKey: "X-Custom-ID",
+					},
+				},
+			},
+			requestHeader:      "X-Custom-ID",
+			requestHeaderValue: "custom-id-123",
+			expectedInContext:  "custom-id-123",
+			expectedInResponse: "custom-id-123",
+			checkResponseKey:   "X-Custom-ID",
+		},
+		{
+			name: "uses custom generator",
+			config: []Config{
+				{
+					Config: goctxid.Config{
+						Generator: func() string {
+							return "custom-generated-id"
+						},
+					},
+				},
+			},
+			requestHeader:      "",
+			requestHeaderValue: "",
+			expectedInContext:  "custom-generated-id",
+			expectedInResponse: "custom-generated-id",
+			checkResponseKey:   goctxid.DefaultHeaderKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+
+			// Apply middleware
+			var handler http.Handler
+			if tt.config != nil {
+				handler = New(tt.config...)(mux)
+			} else {
+				handler = New()(mux)
+			}
+
+			// Test handler that checks context
+			var contextID string
+			mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+				id, exists := goctxid.FromContext(r.Context())
+				if !exists {
+					t.Error("Correlation ID not found in context")
+				}
+				contextID = id
+				_, _ = w.Write([]byte("OK"))
+			})
+
+			// Create request
+			req := httptest.NewRequest("GET", "/test", nil)
+			if tt.requestHeader != "" {
+				req.Header.Set(tt.requestHeader, tt.requestHeaderValue)
+			}
+			rec := httptest.NewRecorder()
+
+			// Execute request
+			handler.ServeHTTP(rec, req)
+
+			// Check response header
+			responseID := rec.Header().Get(tt.checkResponseKey)
+			if responseID == "" {
+				t.Error("Response header does not contain correlation ID")
+			}
+
+			// Check expected values
+			if tt.expectedInContext != "" {
+				if contextID != tt.expectedInContext {
+					t.Errorf("Context ID = %v, want %v", contextID, tt.expectedInContext)
+				}
+			} else {
+				// Just verify it's not empty
+				if contextID == "" {
+					t.Error("Context ID is empty")
+				}
+			}
+
+			if tt.expectedInResponse != "" {
+				if responseID != tt.expectedInResponse {
+					t.Errorf("Response header ID = %v, want %v", responseID, tt.expectedInResponse)
+				}
+			}
+
+			// Verify context and response have same ID
+			if contextID != responseID {
+				t.Errorf("Context ID (%v) != Response ID (%v)", contextID, responseID)
+			}
+		})
+	}
+}
+
+func TestConfigDefault(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            []Config
+		expectedHeaderKey string
+		testGenerator     bool
+	}{
+		{
+			name:              "uses defaults when no config provided",
+			config:            nil,
+			expectedHeaderKey: goctxid.DefaultHeaderKey,
+			testGenerator:     true,
+		},
+		{
+			name:              "uses defaults when empty config provided",
+			config:            []Config{{}},
+			expectedHeaderKey: goctxid.DefaultHeaderKey,
+			testGenerator:     true,
+		},
+		{
+			name: "uses custom header key",
+			config: []Config{
+				{Config: goctxid.Config{HeaderKey: "X-Request-ID"}},
+			},
+			expectedHeaderKey: "X-Request-ID",
+			testGenerator:     true,
+		},
+		{
+			name: "uses custom generator",
+			config: []Config{
+				{
+					Config: goctxid.Config{
+						Generator: func() string { return "test" },
+					},
+				},
+			},
+			expectedHeaderKey: goctxid.DefaultHeaderKey,
+			testGenerator:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := configDefault(tt.config...)
+
+			if cfg.HeaderKey != tt.expectedHeaderKey {
+				t.Errorf("HeaderKey = %v, want %v", cfg.HeaderKey, tt.expectedHeaderKey)
+			}
+
+			if cfg.Generator == nil {
+				t.Error("Generator is nil")
+			}
+
+			if tt.testGenerator {
+				// Test that default generator works
+				id := cfg.Generator()
+				if id == "" {
+					t.Error("Generator returned empty string")
+				}
+			}
+		})
+	}
+}
+
+func TestMiddlewareChaining(t *testing.T) {
+	mux := http.NewServeMux()
+
+	var firstHandlerID, secondHandlerID string
+
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := goctxid.FromContext(r.Context())
+		secondHandlerID = id
+		_, _ = w.Write([]byte("OK"))
+	})
+
+	// Inner middleware wrapped by goctxid
+	inner := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, _ := goctxid.FromContext(r.Context())
+			firstHandlerID = id
+			next.ServeHTTP(w, r)
+		})
+	}
+	handler := New()(inner(mux))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if firstHandlerID == "" || secondHandlerID == "" {
+		t.Error("Correlation ID not propagated through middleware chain")
+	}
+
+	if firstHandlerID != secondHandlerID {
+		t.Errorf("Correlation ID changed in middleware chain: %v != %v", firstHandlerID, secondHandlerID)
+	}
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	mux := http.NewServeMux()
+
+	var mu sync.Mutex
+	seenIDs := make(map[string]bool)
+
+	handler := New()(mux)
+
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		id, exists := goctxid.FromContext(r.Context())
+		if !exists {
+			t.Error("Correlation ID not found in context")
+		}
+
+		mu.Lock()
+		seenIDs[id] = true
+		mu.Unlock()
+
+		_, _ = w.Write([]byte(id))
+	})
+
+	// Make multiple concurrent requests
+	var wg sync.WaitGroup
+	numRequests := 50
+
+	for i := 0; i < numRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest("GET", "/test", nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			body, _ := io.ReadAll(rec.Body)
+			responseID := string(body)
+
+			if responseID == "" {
+				t.Error("Empty correlation ID in response")
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Verify we got unique IDs for each request
+	mu.Lock()
+	uniqueCount := len(seenIDs)
+	mu.Unlock()
+
+	if uniqueCount != numRequests {
+		t.Errorf("Expected %d unique IDs, got %d", numRequests, uniqueCount)
+	}
+}
+
+func TestGeneratorThreadSafety(t *testing.T) {
+	// Test that custom generator is called safely from multiple goroutines
+	var callCount int
+	var mu sync.Mutex
+
+	generator := func() string {
+		mu.Lock()
+		callCount++
+		mu.Unlock()
+		return uuid.NewString() // Use a different ID for each call
+	}
+
+	mux := http.NewServeMux()
+	handler := New(Config{Config: goctxid.Config{Generator: generator}})(mux)
+
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK"))
+	})
+
+	var wg sync.WaitGroup
+	numRequests := 20
+
+	for i := 0; i < numRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest("GET", "/test", nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+		}()
+	}
+
+	wg.Wait()
+
+	mu.Lock()
+	finalCount := callCount
+	mu.Unlock()
+
+	if finalCount != numRequests {
+		t.Errorf("Generator called %d times, expected %d", finalCount, numRequests)
+	}
+}
+
+func BenchmarkBaseline(b *testing.B) {
+	// Baseline: net/http app WITHOUT goctxid middleware
+	mux := http.NewServeMux()
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK"))
+	})
+	handler := http.Handler(mux)
+
+	req := httptest.NewRequest("GET", "/test", nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+	}
+}
+
+func BenchmarkMiddleware(b *testing.B) {
+	// With goctxid middleware - generates new ID
+	mux := http.NewServeMux()
+	handler := New()(mux)
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK"))
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+	}
+}
+
+func BenchmarkMiddlewareWithExistingID(b *testing.B) {
+	// With goctxid middleware - uses existing ID from header
+	mux := http.NewServeMux()
+	handler := New()(mux)
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK"))
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set(goctxid.DefaultHeaderKey, "existing-id-123")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+	}
+}
+
+func BenchmarkMiddlewareWithContextAccess(b *testing.B) {
+	// With goctxid middleware - accessing ID from context in handler
+	mux := http.NewServeMux()
+	handler := New()(mux)
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		// Simulate real-world usage: accessing the correlation ID
+		id, _ := goctxid.FromContext(r.Context())
+		_ = id // Use the ID
+		_, _ = w.Write([]byte("OK"))
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+	}
+}
+
+// TestGoroutineSafety tests that context-based approach is safe for goroutines
+func TestGoroutineSafety(t *testing.T) {
+	mux := http.NewServeMux()
+	handler := New()(mux)
+
+	var wg sync.WaitGroup
+	capturedIDs := make([]string, 0)
+	var mu sync.Mutex
+
+	mux.HandleFunc("/safe", func(w http.ResponseWriter, r *http.Request) {
+		// Get the context - it's immutable and safe to pass to goroutines
+		ctx := r.Context()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Small delay to ensure handler completes first
+			time.Sleep(10 * time.Millisecond)
+
+			// Access correlation ID from context - this is safe
+			id := goctxid.MustFromContext(ctx)
+
+			mu.Lock()
+			capturedIDs = append(capturedIDs, id)
+			mu.Unlock()
+		}()
+
+		_, _ = w.Write([]byte("OK"))
+	})
+
+	// Make request
+	req := httptest.NewRequest("GET", "/safe", nil)
+	req.Header.Set(goctxid.DefaultHeaderKey, "nethttp-test-id")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	// Wait for goroutine to complete
+	wg.Wait()
+
+	// Verify the goroutine captured the correct ID
+	if len(capturedIDs) != 1 {
+		t.Fatalf("Expected 1 captured ID, got %d", len(capturedIDs))
+	}
+
+	if capturedIDs[0] != "nethttp-test-id" {
+		t.Errorf("Expected captured ID to be 'nethttp-test-id', got '%s'", capturedIDs[0])
+	}
+}
+
+// TestMultipleGoroutines tests that context can be safely shared across multiple goroutines
+func TestMultipleGoroutines(t *testing.T) {
+	mux := http.NewServeMux()
+	handler := New()(mux)
+
+	const numGoroutines = 10
+	var wg sync.WaitGroup
+	capturedIDs := make([]string, 0, numGoroutines)
+	var mu sync.Mutex
+
+	mux.HandleFunc("/multi", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Spawn multiple goroutines
+		for i := 0; i < numGoroutines; i++ {
+			wg.Add(1)
+			go func(index int) {
+				defer wg.Done()
+				time.Sleep(time.Duration(index) * time.Millisecond)
+
+				// All goroutines should get the same ID
+				id := goctxid.MustFromContext(ctx)
+
+				mu.Lock()
+				capturedIDs = append(capturedIDs, id)
+				mu.Unlock()
+			}(i)
+		}
+
+		_, _ = w.Write([]byte("OK"))
+	})
+
+	req := httptest.NewRequest("GET", "/multi", nil)
+	req.Header.Set(goctxid.DefaultHeaderKey, "nethttp-multi-id")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	wg.Wait()
+
+	// Verify all goroutines captured the same ID
+	if len(capturedIDs) != numGoroutines {
+		t.Fatalf("Expected %d captured IDs, got %d", numGoroutines, len(capturedIDs))
+	}
+
+	for i, id := range capturedIDs {
+		if id != "nethttp-multi-id" {
+			t.Errorf("Goroutine %d: expected 'nethttp-multi-id', got '%s'", i, id)
+		}
+	}
+}
+
+// TestConcurrentRequestsWithGoroutines tests that correlation IDs don't get mixed up
+// when multiple concurrent requests each spawn goroutines that access the ID
+func TestConcurrentRequestsWithGoroutines(t *testing.T) {
+	mux := http.NewServeMux()
+	handler := New()(mux)
+
+	type result struct {
+		requestID  string
+		capturedID string
+	}
+
+	results := make([]result, 0)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		// Get the context - it's immutable and safe to pass to goroutines
+		ctx := r.Context()
+		requestID := goctxid.MustFromContext(ctx)
+
+		// Uncomment to debug:
+		// t.Logf("Handler: requestID = %s, ctx = %p", requestID, ctx)
+
+		wg.Add(1)
+		go func(capturedCtx context.Context, expectedID string) {
+			defer wg.Done()
+			// Delay to simulate async processing and ensure handler completes first
+			time.Sleep(50 * time.Millisecond)
+
+			// Access ID from goroutine - should still be the correct ID
+			capturedID := goctxid.MustFromContext(capturedCtx)
+
+			// Uncomment to debug:
+			// t.Logf("Goroutine: expectedID = %s, capturedID = %s, ctx = %p", expectedID, capturedID, capturedCtx)
+
+			mu.Lock()
+			results = append(results, result{
+				requestID:  expectedID,
+				capturedID: capturedID,
+			})
+			mu.Unlock()
+		}(ctx, requestID)
+
+		_, _ = w.Write([]byte("OK"))
+	})
+
+	// Send multiple concurrent requests with different IDs
+	numRequests := 20
+	requestWg := sync.WaitGroup{}
+
+	for i := 0; i < numRequests; i++ {
+		requestWg.Add(1)
+		go func(index int) {
+			defer requestWg.Done()
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set(goctxid.DefaultHeaderKey, fmt.Sprintf("request-%d", index))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+		}(i)
+	}
+
+	// Wait for all requests to complete
+	requestWg.Wait()
+
+	// Wait for all goroutines to complete
+	wg.Wait()
+
+	// Verify: Each goroutine should capture the SAME ID as its request
+	if len(results) != numRequests {
+		t.Fatalf("Expected %d results, got %d", numRequests, len(results))
+	}
+
+	// Uncomment to debug:
+	// t.Logf("=== Results ===")
+	// for i, r := range results {
+	// 	t.Logf("[%d] requestID: %s, capturedID: %s", i, r.requestID, r.capturedID)
+	// }
+
+	// Check that no IDs got mixed up
+	for _, r := range results {
+		if r.requestID != r.capturedID {
+			t.Errorf("ID mismatch! Request had '%s' but goroutine captured '%s'",
+				r.requestID, r.capturedID)
+		}
+	}
+
+	// Verify all IDs are unique (no duplicates)
+	seenIDs := make(map[string]bool)
+	for _, r := range results {
+		if seenIDs[r.requestID] {
+			t.Errorf("Duplicate ID found: %s - This means contexts got mixed up!", r.requestID)
+		}
+		seenIDs[r.requestID] = true
+	}
+
+	if len(seenIDs) != numRequests {
+		t.Errorf("Expected %d unique IDs, got %d - Contexts got mixed up!", numRequests, len(seenIDs))
+	}
+
+	// Uncomment to debug:
+	// t.Logf("✅ Test passed! All %d requests had unique IDs and goroutines captured correct values", numRequests)
+}
+
+// TestReExportedFunctions tests that re-exported functions work correctly
+// This allows users to use goctxid_nethttp.FromContext() instead of importing goctxid
+func TestReExportedFunctions(t *testing.T) {
+	ctx := context.Background()
+
+	// Test NewContext
+	testID := "test-correlation-id-123"
+	newCtx := NewContext(ctx, testID)
+
+	// Test FromContext
+	retrievedID, exists := FromContext(newCtx)
+	if !exists {
+		t.Error("FromContext should return true for existing ID")
+	}
+	if retrievedID != testID {
+		t.Errorf("FromContext returned wrong ID: got %s, want %s", retrievedID, testID)
+	}
+
+	// Test MustFromContext
+	mustID := MustFromContext(newCtx)
+	if mustID != testID {
+		t.Errorf("MustFromContext returned wrong ID: got %s, want %s", mustID, testID)
+	}
+
+	// Test MustFromContext with empty context
+	emptyID := MustFromContext(ctx)
+	if emptyID != "" {
+		t.Errorf("MustFromContext should return empty string for context without ID, got %s", emptyID)
+	}
+
+	// Test re-exported constants
+	if DefaultHeaderKey != "X-Correlation-ID" {
+		t.Errorf("DefaultHeaderKey should be X-Correlation-ID, got %s", DefaultHeaderKey)
+	}
+
+	// Test re-exported generators
+	if DefaultGenerator == nil {
+		t.Error("DefaultGenerator should not be nil")
+	}
+	if FastGenerator == nil {
+		t.Error("FastGenerator should not be nil")
+	}
+
+	// Test that generators actually work
+	id1 := DefaultGenerator()
+	if id1 == "" {
+		t.Error("DefaultGenerator should return non-empty ID")
+	}
+
+	id2 := FastGenerator()
+	if id2 == "" {
+		t.Error("FastGenerator should return non-empty ID")
+	}
+
+	id3 := UUIDv7Generator()
+	if id3 == "" {
+		t.Error("UUIDv7Generator should return non-empty ID")
+	}
+
+	id4 := ULIDGenerator()
+	if id4 == "" {
+		t.Error("ULIDGenerator should return non-empty ID")
+	}
+
+	id5 := FastOpaqueGenerator()
+	if id5 == "" {
+		t.Error("FastOpaqueGenerator should return non-empty ID")
+	}
+
+	id6 := BufferedGenerator()
+	if id6 == "" {
+		t.Error("BufferedGenerator should return non-empty ID")
+	}
+}
+
+// TestGetCorrelationID tests the GetCorrelationID convenience function
+func TestGetCorrelationID(t *testing.T) {
+	mux := http.NewServeMux()
+	handler := New()(mux)
+
+	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+		// Test GetCorrelationID function
+		id := GetCorrelationID(r)
+		if id == "" {
+			t.Error("GetCorrelationID should return non-empty ID")
+		}
+
+		// Verify it matches what's in the context
+		idFromContext := MustFromContext(r.Context())
+		if id != idFromContext {
+			t.Errorf("GetCorrelationID (%s) should match MustFromContext (%s)", id, idFromContext)
+		}
+
+		_, _ = w.Write([]byte(id))
+	})
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != 200 {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+}
+
+// TestNextFunction tests the Next configuration option
+func TestNextFunction(t *testing.T) {
+	mux := http.NewServeMux()
+
+	// Configure middleware to skip requests to /skip path
+	handler := New(Config{
+		Next: func(r *http.Request) bool {
+			return r.URL.Path == "/skip"
+		},
+	})(mux)
+
+	mux.HandleFunc("/skip", func(w http.ResponseWriter, r *http.Request) {
+		// This should NOT have a correlation ID because middleware was skipped
+		id := GetCorrelationID(r)
+		if id != "" {
+			t.Errorf("Expected empty ID for skipped path, got %s", id)
+		}
+		_, _ = w.Write([]byte("skipped"))
+	})
+
+	mux.HandleFunc("/process", func(w http.ResponseWriter, r *http.Request) {
+		// This SHOULD have a correlation ID
+		id := GetCorrelationID(r)
+		if id == "" {
+			t.Error("Expected non-empty ID for processed path")
+		}
+		_, _ = w.Write([]byte(id))
+	})
+
+	// Test skipped path
+	req := httptest.NewRequest("GET", "/skip", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != 200 {
+		t.Errorf("Expected status 200 for /skip, got %d", rec.Code)
+	}
+
+	// Verify no correlation ID header was set
+	if rec.Header().Get(DefaultHeaderKey) != "" {
+		t.Errorf("Expected no correlation ID header for skipped path, got %s", rec.Header().Get(DefaultHeaderKey))
+	}
+
+	// Test processed path
+	req = httptest.NewRequest("GET", "/process", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != 200 {
+		t.Errorf("Expected status 200 for /process, got %d", rec.Code)
+	}
+
+	// Verify correlation ID header was set
+	if rec.Header().Get(DefaultHeaderKey) == "" {
+		t.Error("Expected correlation ID header for processed path")
+	}
+}
+
+// TestValidator tests how invalid inbound IDs are handled by each policy
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name            string
+		policy          goctxid.InvalidPolicy
+		inbound         string
+		expectedStatus  int
+		expectedID      string
+		expectedInvalid bool
+	}{
+		{
+			name:           "accepts valid ID",
+			policy:         goctxid.InvalidReject,
+			inbound:        "123e4567-e89b-12d3-a456-426614174000",
+			expectedStatus: http.StatusOK,
+			expectedID:     "123e4567-e89b-12d3-a456-426614174000",
+		},
+		{
+			name:           "regenerates invalid ID",
+			policy:         goctxid.InvalidRegenerate,
+			inbound:        "not-a-uuid",
+			expectedStatus: http.StatusOK,
+			expectedID:     "generated-id",
+		},
+		{
+			name:           "rejects invalid ID",
+			policy:         goctxid.InvalidReject,
+			inbound:        "not-a-uuid",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:            "keeps and flags invalid ID",
+			policy:          goctxid.InvalidKeep,
+			inbound:         "not-a-uuid",
+			expectedStatus:  http.StatusOK,
+			expectedID:      "not-a-uuid",
+			expectedInvalid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			handler := New(Config{
+				Config: goctxid.Config{
+					Generator: func() string { return "generated-id" },
+					Validator: goctxid.ValidateUUID,
+					OnInvalid: tt.policy,
+				},
+			})(mux)
+
+			var contextID string
+			var invalid bool
+			mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+				contextID = GetCorrelationID(r)
+				invalid = IsInvalid(r.Context())
+				_, _ = w.Write([]byte("OK"))
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set(DefaultHeaderKey, tt.inbound)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			if responseID := rec.Header().Get(DefaultHeaderKey); responseID != tt.expectedID {
+				t.Errorf("Response header ID = %v, want %v", responseID, tt.expectedID)
+			}
+			if invalid != tt.expectedInvalid {
+				t.Errorf("IsInvalid = %v, want %v", invalid, tt.expectedInvalid)
+			}
+		})
+	}
+}
+
+func TestTraceParent(t *testing.T) {
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	tests := []struct {
+		name               string
+		config             goctxid.Config
+		inboundTraceParent string
+		expectedID         string
+		expectedEmit       bool
+	}{
+		{
+			name:               "ignores traceparent by default",
+			config:             goctxid.Config{},
+			inboundTraceParent: traceParent,
+			expectedID:         "generated-id",
+		},
+		{
+			name:               "derives ID from traceparent",
+			config:             goctxid.Config{UseTraceParent: true},
+			inboundTraceParent: traceParent,
+			expectedID:         traceID,
+		},
+		{
+			name:               "continues inbound trace",
+			config:             goctxid.Config{UseTraceParent: true, EmitTraceParent: true},
+			inboundTraceParent: traceParent,
+			expectedID:         traceID,
+			expectedEmit:       true,
+		},
+		{
+			name:         "starts new trace",
+			config:       goctxid.Config{EmitTraceParent: true},
+			expectedID:   "generated-id",
+			expectedEmit: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.config
+			cfg.Generator = func() string { return "generated-id" }
+			mux := http.NewServeMux()
+			handler := New(Config{Config: cfg})(mux)
+
+			var contextID string
+			var contextTraceParent goctxid.TraceParent
+			var ok bool
+			mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+				contextID = GetCorrelationID(r)
+				contextTraceParent, ok = goctxid.TraceParentFromContext(r.Context())
+				_, _ = w.Write([]byte("OK"))
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			if tt.inboundTraceParent != "" {
+				req.Header.Set(goctxid.TraceParentHeader, tt.inboundTraceParent)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			if ok != tt.expectedEmit {
+				t.Fatalf("Traceparent in context = %v, want %v", ok, tt.expectedEmit)
+			}
+
+			responseTraceParent := rec.Header().Get(goctxid.TraceParentHeader)
+			if !tt.expectedEmit {
+				if responseTraceParent != "" {
+					t.Errorf("Unexpected traceparent response header %v", responseTraceParent)
+				}
+				return
+			}
+			if responseTraceParent != contextTraceParent.String() {
+				t.Errorf("Response traceparent = %v, want %v", responseTraceParent, contextTraceParent.String())
+			}
+			if tt.inboundTraceParent != "" {
+				if contextTraceParent.TraceIDString() != traceID {
+					t.Errorf("Trace ID = %v, want %v", contextTraceParent.TraceIDString(), traceID)
+				}
+				if responseTraceParent == tt.inboundTraceParent {
+					t.Error("Expected a new parent-id in the response traceparent")
+				}
+			}
+		})
+	}
+}
+
+func TestInboundHeaderKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		expectedID string
+	}{
+		{
+			name:       "uses first listed header",
+			headers:    map[string]string{DefaultHeaderKey: "correlation-id", "X-Request-ID": "request-id"},
+			expectedID: "correlation-id",
+		},
+		{
+			name:       "falls back to later header",
+			headers:    map[string]string{"X-Request-ID": "request-id"},
+			expectedID: "request-id",
+		},
+		{
+			name:       "generates ID without listed headers",
+			headers:    map[string]string{"X-Other-ID": "other-id"},
+			expectedID: "generated-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := goctxid.Config{
+				InboundHeaderKeys: []string{DefaultHeaderKey, "X-Request-ID"},
+				Generator:         func() string { return "generated-id" },
+			}
+			mux := http.NewServeMux()
+			handler := New(Config{Config: cfg})(mux)
+
+			var contextID string
+			mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+				contextID = GetCorrelationID(r)
+				_, _ = w.Write([]byte("OK"))
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			// The response always uses the canonical header
+			if responseID := rec.Header().Get(DefaultHeaderKey); responseID != tt.expectedID {
+				t.Errorf("Response header ID = %v, want %v", responseID, tt.expectedID)
+			}
+		})
+	}
+}
+
+// TestRequestGenerator tests that RequestGenerator sees the request
+func TestRequestGenerator(t *testing.T) {
+	var got goctxid.RequestInfo
+	cfg := goctxid.Config{
+		RequestGenerator: func(ctx context.Context, r goctxid.RequestInfo) string {
+			got = r
+			return r.Header("X-Tenant") + "-generated"
+		},
+	}
+
+	tests := []struct {
+		name       string
+		inbound    string
+		expectedID string
+	}{
+		{name: "generates ID from request", inbound: "", expectedID: "acme-generated"},
+		{name: "keeps inbound ID", inbound: "client-id", expectedID: "client-id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = goctxid.RequestInfo{}
+			var contextID string
+			mux := http.NewServeMux()
+			handler := New(Config{Config: cfg})(mux)
+			mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+				contextID = GetCorrelationID(r)
+				_, _ = w.Write([]byte("OK"))
+			})
+
+			req := httptest.NewRequest("POST", "/test", nil)
+			req.Header.Set("X-Tenant", "acme")
+			if tt.inbound != "" {
+				req.Header.Set(DefaultHeaderKey, tt.inbound)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			if responseID := rec.Header().Get(DefaultHeaderKey); responseID != tt.expectedID {
+				t.Errorf("Response header ID = %v, want %v", responseID, tt.expectedID)
+			}
+			if tt.inbound != "" {
+				if got.Header != nil {
+					t.Error("RequestGenerator should not be called for an inbound ID")
+				}
+				return
+			}
+			if got.Method != "POST" || got.Path != "/test" {
+				t.Errorf("RequestInfo = %s %s, want POST /test", got.Method, got.Path)
+			}
+			if got.RemoteAddr == "" {
+				t.Error("RequestInfo.RemoteAddr is empty")
+			}
+		})
+	}
+}
+
+// TestFromContextWithInfo tests that the origin of the correlation ID is recorded
+func TestFromContextWithInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		expectedInfo goctxid.IDInfo
+	}{
+		{
+			name:         "generated",
+			headers:      map[string]string{},
+			expectedInfo: goctxid.IDInfo{ID: "generated-id", Origin: OriginGenerated},
+		},
+		{
+			name:         "from inbound header",
+			headers:      map[string]string{"X-Request-ID": "request-id"},
+			expectedInfo: goctxid.IDInfo{ID: "request-id", Origin: OriginHeader, HeaderKey: "X-Request-ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := goctxid.Config{
+				InboundHeaderKeys: []string{DefaultHeaderKey, "X-Request-ID"},
+				Generator:         func() string { return "generated-id" },
+			}
+			var info goctxid.IDInfo
+			mux := http.NewServeMux()
+			handler := New(Config{Config: cfg})(mux)
+			mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+				info, _ = FromContextWithInfo(r.Context())
+				_, _ = w.Write([]byte("OK"))
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if info != tt.expectedInfo {
+				t.Errorf("FromContextWithInfo() = %+v, want %+v", info, tt.expectedInfo)
+			}
+		})
+	}
+}
+
+// TestRequestID tests the per-service request ID next to the upstream correlation ID
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name             string
+		headerKey        string
+		expectedInHeader string
+	}{
+		{name: "echoes request ID", headerKey: "X-Request-ID", expectedInHeader: "request-id"},
+		{name: "does not echo request ID without header key", headerKey: "", expectedInHeader: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := goctxid.Config{
+				RequestIDGenerator: func() string { return "request-id" },
+				RequestIDHeaderKey: tt.headerKey,
+			}
+			var contextID, requestID string
+			mux := http.NewServeMux()
+			handler := New(Config{Config: cfg})(mux)
+			mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+				contextID = GetCorrelationID(r)
+				requestID = MustRequestIDFromContext(r.Context())
+				_, _ = w.Write([]byte("OK"))
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set(DefaultHeaderKey, "upstream-id")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if contextID != "upstream-id" {
+				t.Errorf("Context ID = %v, want %v", contextID, "upstream-id")
+			}
+			if requestID != "request-id" {
+				t.Errorf("Request ID = %v, want %v", requestID, "request-id")
+			}
+			if responseID := rec.Header().Get(DefaultHeaderKey); responseID != "upstream-id" {
+				t.Errorf("Response header ID = %v, want %v", responseID, "upstream-id")
+			}
+			if got := rec.Header().Get("X-Request-ID"); got != tt.expectedInHeader {
+				t.Errorf("Response request ID = %v, want %v", got, tt.expectedInHeader)
+			}
+		})
+	}
+}
+
+func TestTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name         string
+		trusted      string
+		forwardedFor string
+		expectedID   string
+	}{
+		{name: "honors ID from trusted proxy", trusted: "192.0.2.1/32", expectedID: "upstream-id"},
+		{name: "regenerates ID from untrusted peer", trusted: "10.0.0.0/8", expectedID: "generated-id"},
+		{name: "ignores spoofed X-Forwarded-For", trusted: "10.0.0.0/8", forwardedFor: "10.0.0.1", expectedID: "generated-id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := goctxid.Config{
+				Generator:      func() string { return "generated-id" },
+				TrustedProxies: []netip.Prefix{netip.MustParsePrefix(tt.trusted)},
+			}
+			var contextID string
+			mux := http.NewServeMux()
+			handler := New(Config{Config: cfg})(mux)
+			mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+				contextID = GetCorrelationID(r)
+				_, _ = w.Write([]byte("OK"))
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set(DefaultHeaderKey, "upstream-id")
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			if responseID := rec.Header().Get(DefaultHeaderKey); responseID != tt.expectedID {
+				t.Errorf("Response header ID = %v, want %v", responseID, tt.expectedID)
+			}
+		})
+	}
+}
+
+func TestTrustedProxiesTraceParent(t *testing.T) {
+	const generatedID = "550e8400-e29b-41d4-a716-446655440000"
+	const inbound = "00-deadbeefdeadbeefdeadbeefdeadbeef-00f067aa0ba902b7-01"
+
+	tests := []struct {
+		name            string
+		trusted         string
+		expectedTraceID string
+	}{
+		{name: "continues trace from trusted proxy", trusted: "192.0.2.1/32", expectedTraceID: "deadbeefdeadbeefdeadbeefdeadbeef"},
+		{name: "starts new trace for untrusted peer", trusted: "10.0.0.0/8", expectedTraceID: "550e8400e29b41d4a716446655440000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := goctxid.Config{
+				Generator:       func() string { return generatedID },
+				TrustedProxies:  []netip.Prefix{netip.MustParsePrefix(tt.trusted)},
+				EmitTraceParent: true,
+			}
+			var contextTraceParent goctxid.TraceParent
+			mux := http.NewServeMux()
+			handler := New(Config{Config: cfg})(mux)
+			mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+				contextTraceParent, _ = goctxid.TraceParentFromContext(r.Context())
+				_, _ = w.Write([]byte("OK"))
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set(goctxid.TraceParentHeader, inbound)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got := contextTraceParent.TraceIDString(); got != tt.expectedTraceID {
+				t.Errorf("Context trace-id = %v, want %v", got, tt.expectedTraceID)
+			}
+			response, err := goctxid.ParseTraceParent(rec.Header().Get(goctxid.TraceParentHeader))
+			if err != nil {
+				t.Fatalf("ParseTraceParent() error = %v", err)
+			}
+			if got := response.TraceIDString(); got != tt.expectedTraceID {
+				t.Errorf("Response trace-id = %v, want %v", got, tt.expectedTraceID)
+			}
+		})
+	}
+}
+
+func TestSigner(t *testing.T) {
+	signer, err := goctxid.NewSigner([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		inbound    string
+		expectedID string
+	}{
+		{name: "honors signed ID", inbound: signer.Sign("upstream-id"), expectedID: signer.Sign("upstream-id")},
+		{name: "regenerates forged ID", inbound: "upstream-id.AAAAAAAAAAAAAAAA", expectedID: signer.Sign("generated-id")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := goctxid.Config{
+				Generator: func() string { return "generated-id" },
+				Signer:    signer,
+			}
+			var contextID string
+			mux := http.NewServeMux()
+			handler := New(Config{Config: cfg})(mux)
+			mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
+				contextID = GetCorrelationID(r)
+				_, _ = w.Write([]byte("OK"))
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set(DefaultHeaderKey, tt.inbound)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if contextID != tt.expectedID {
+				t.Errorf("Context ID = %v, want %v", contextID, tt.expectedID)
+			}
+			if responseID := rec.Header().Get(DefaultHeaderKey); responseID != tt.expectedID {
+				t.Errorf("Response header ID = %v, want %v", responseID, tt.expectedID)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+// Package sql wraps database/sql drivers to prepend a sqlcommenter comment
+// carrying the correlation ID to every query, e.g.
+//
+//	/*correlation_id='550e8400-e29b-41d4-a716-446655440000'*/ SELECT ...
+//
+// so queries in database logs (slow query log, pg_stat_activity) can be
+// traced back to the request that ran them.
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/hiiamtin/goctxid"
+)
+
+// DefaultKey is the default sqlcommenter key of the correlation ID
+const DefaultKey = "correlation_id"
+
+// Config defines the config for the driver wrapper
+type Config struct {
+	// Key is the sqlcommenter key used for the correlation ID
+	//
+	// Optional. Default: "correlation_id"
+	Key string
+
+	// SkipPrepared disables the comment on statements prepared with
+	// PrepareContext (db.Prepare, tx.Prepare). A per-request comment makes every
+	// statement text unique, which defeats statement caches keyed by query text.
+	// Note that database/sql falls back to a prepared statement when the driver
+	// cannot execute queries directly; those queries are not commented either.
+	//
+	// Optional. Default: false
+	SkipPrepared bool
+}
+
+// configDefault is a helper function that merges the provided config with the default config
+func configDefault(config ...Config) Config {
+
+	var cfg Config
+
+	// If a config is provided, use it
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Check and fill in default values
+	if cfg.Key == "" {
+		cfg.Key = DefaultKey
+	}
+
+	return cfg
+}
+
+// comment prepends the sqlcommenter comment to query when ctx carries a
+// correlation ID. Key and value are URL-encoded, so they can never contain
+// a quote or close the comment.
+func (cfg Config) comment(ctx context.Context, query string) string {
+	id, ok := goctxid.FromContext(ctx)
+	if !ok || id == "" {
+		return query
+	}
+
+	key := url.PathEscape(cfg.Key)
+	value := url.PathEscape(id)
+
+	var b strings.Builder
+	b.Grow(len(key) + len(value) + len(query) + 8)
+	b.WriteString("/*")
+	b.WriteString(key)
+	b.WriteString("='")
+	b.WriteString(value)
+	b.WriteString("'*/ ")
+	b.WriteString(query)
+	return b.String()
+}
+
+// NewConnector wraps a driver.Connector so that queries run on its
+// connections carry the correlation ID of the query context.
+//
+// Example usage:
+//
+//	connector, err := pq.NewConnector(dsn)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	db := sql.OpenDB(goctxid_sql.NewConnector(connector))
+//
+//	// Inside a handler: the query is logged with the request's correlation ID
+//	rows, err := db.QueryContext(ctx, "SELECT * FROM users WHERE id = $1", userID)
+func NewConnector(base driver.Connector, config ...Config) driver.Connector {
+	return &connector{base: base, cfg: configDefault(config...)}
+}
+
+// OpenDB is a shortcut for sql.OpenDB(NewConnector(base, config...))
+func OpenDB(base driver.Connector, config ...Config) *sql.DB {
+	return sql.OpenDB(NewConnector(base, config...))
+}
+
+// WrapDriver wraps a driver.Driver for use with sql.Register, for drivers that
+// do not provide a driver.Connector.
+//
+// Example usage:
+//
+//	sql.Register("postgres-goctxid", goctxid_sql.WrapDriver(&pq.Driver{}))
+//	db, err := sql.Open("postgres-goctxid", dsn)
+func WrapDriver(base driver.Driver, config ...Config) driver.Driver {
+	return &wrappedDriver{base: base, cfg: configDefault(config...)}
+}
+
+// connector is a driver.Connector returning wrapped connections
+type connector struct {
+	base driver.Connector
+	cfg  Config
+}
+
+// Connect implements driver.Connector
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedConn{Conn: conn, cfg: c.cfg}, nil
+}
+
+// Driver implements driver.Connector
+func (c *connector) Driver() driver.Driver {
+	return &wrappedDriver{base: c.base.Driver(), cfg: c.cfg}
+}
+
+// Close implements io.Closer, so that sql.DB.Close closes the wrapped
+// connector when it holds resources (e.g. a pool or background goroutines)
+func (c *connector) Close() error {
+	if closer, ok := c.base.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// wrappedDriver is a driver.Driver returning wrapped connections
+type wrappedDriver struct {
+	base driver.Driver
+	cfg  Config
+}
+
+// Open implements driver.Driver
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.base.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &wrappedConn{Conn: conn, cfg: d.cfg}, nil
+}
+
+// wrappedConn adds the comment to queries and forwards the optional driver
+// interfaces to the wrapped connection
+type wrappedConn struct {
+	driver.Conn
+	cfg Config
+}
+
+// PrepareContext implements driver.ConnPrepareContext
+func (c *wrappedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if !c.cfg.SkipPrepared {
+		query = c.cfg.comment(ctx, query)
+	}
+	if prep, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return prep.PrepareContext(ctx, query)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.Conn.Prepare(query)
+}
+
+// ExecContext implements driver.ExecerContext
+func (c *wrappedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := c.Conn.(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, c.cfg.comment(ctx, query), args)
+	}
+	execer, ok := c.Conn.(driver.Execer) //nolint:staticcheck // Fallback for drivers without ExecerContext
+	if !ok {
+		// database/sql falls back to PrepareContext
+		return nil, driver.ErrSkip
+	}
+	values, err := namedValuesToValues(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return execer.Exec(c.cfg.comment(ctx, query), values)
+}
+
+// QueryContext implements driver.QueryerContext
+func (c *wrappedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := c.Conn.(driver.QueryerContext); ok {
+		return queryer.QueryContext(ctx, c.cfg.comment(ctx, query), args)
+	}
+	queryer, ok := c.Conn.(driver.Queryer) //nolint:staticcheck // Fallback for drivers without QueryerContext
+	if !ok {
+		// database/sql falls back to PrepareContext
+		return nil, driver.ErrSkip
+	}
+	values, err := namedValuesToValues(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return queryer.Query(c.cfg.comment(ctx, query), values)
+}
+
+// namedValuesToValues converts arguments for the legacy driver.Execer and
+// driver.Queryer interfaces, which do not support named parameters
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// BeginTx implements driver.ConnBeginTx
+func (c *wrappedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	// Same restrictions as database/sql for drivers without ConnBeginTx
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errors.New("sql: driver does not support non-default isolation level")
+	}
+	if opts.ReadOnly {
+		return nil, errors.New("sql: driver does not support read-only transactions")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.Conn.Begin() //nolint:staticcheck // Fallback for drivers without ConnBeginTx
+}
+
+// Ping implements driver.Pinger
+func (c *wrappedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession implements driver.SessionResetter
+func (c *wrappedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid implements driver.Validator
+func (c *wrappedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// CheckNamedValue implements driver.NamedValueChecker
+func (c *wrappedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	// database/sql uses its default conversion
+	return driver.ErrSkip
+}
@@ -0,0 +1,328 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hiiamtin/goctxid"
+)
+
+// fakeDriver records the queries it receives. With basic set, its connections
+// only implement driver.Conn, so database/sql falls back to Prepare. With
+// legacy set, they implement the legacy driver.Execer and driver.Queryer.
+type fakeDriver struct {
+	mu       sync.Mutex
+	queries  []string
+	prepared int
+	basic    bool
+	legacy   bool
+}
+
+func (d *fakeDriver) record(query string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queries = append(d.queries, query)
+}
+
+func (d *fakeDriver) lastQuery() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.queries) == 0 {
+		return ""
+	}
+	return d.queries[len(d.queries)-1]
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	if d.basic {
+		return &fakeBasicConn{d: d}, nil
+	}
+	if d.legacy {
+		return &fakeLegacyConn{fakeBasicConn{d: d}}, nil
+	}
+	return &fakeConn{fakeBasicConn{d: d}}, nil
+}
+
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) { return d.Open("") }
+func (d *fakeDriver) Driver() driver.Driver                        { return d }
+
+type fakeBasicConn struct{ d *fakeDriver }
+
+func (c *fakeBasicConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.mu.Lock()
+	c.d.prepared++
+	c.d.mu.Unlock()
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+func (c *fakeBasicConn) Close() error              { return nil }
+func (c *fakeBasicConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeConn struct{ fakeBasicConn }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.d.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.d.record(query)
+	return fakeRows{}, nil
+}
+
+type fakeLegacyConn struct{ fakeBasicConn }
+
+func (c *fakeLegacyConn) Exec(query string, _ []driver.Value) (driver.Result, error) {
+	c.d.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeLegacyConn) Query(query string, _ []driver.Value) (driver.Rows, error) {
+	c.d.record(query)
+	return fakeRows{}, nil
+}
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	s.d.record(s.query)
+	return driver.RowsAffected(1), nil
+}
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.record(s.query)
+	return fakeRows{}, nil
+}
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string         { return []string{"n"} }
+func (fakeRows) Close() error              { return nil }
+func (fakeRows) Next([]driver.Value) error { return io.EOF }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func TestNewConnector(t *testing.T) {
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+	const comment = "/*correlation_id='test-id'*/ "
+
+	tests := []struct {
+		name     string
+		basic    bool
+		legacy   bool
+		config   Config
+		run      func(db *sql.DB) error
+		expected string
+	}{
+		{
+			name: "exec",
+			run: func(db *sql.DB) error {
+				_, err := db.ExecContext(ctx, "DELETE FROM users")
+				return err
+			},
+			expected: comment + "DELETE FROM users",
+		},
+		{
+			name: "query",
+			run: func(db *sql.DB) error {
+				rows, err := db.QueryContext(ctx, "SELECT 1")
+				if err == nil {
+					err = rows.Close()
+				}
+				return err
+			},
+			expected: comment + "SELECT 1",
+		},
+		{
+			name: "prepared statement",
+			run: func(db *sql.DB) error {
+				stmt, err := db.PrepareContext(ctx, "SELECT 1")
+				if err != nil {
+					return err
+				}
+				defer func() { _ = stmt.Close() }()
+				_, err = stmt.Exec()
+				return err
+			},
+			expected: comment + "SELECT 1",
+		},
+		{
+			name:   "prepared statement with SkipPrepared",
+			config: Config{SkipPrepared: true},
+			run: func(db *sql.DB) error {
+				stmt, err := db.PrepareContext(ctx, "SELECT 1")
+				if err != nil {
+					return err
+				}
+				defer func() { _ = stmt.Close() }()
+				_, err = stmt.Exec()
+				return err
+			},
+			expected: "SELECT 1",
+		},
+		{
+			name: "transaction",
+			run: func(db *sql.DB) error {
+				tx, err := db.BeginTx(ctx, nil)
+				if err != nil {
+					return err
+				}
+				if _, err := tx.ExecContext(ctx, "UPDATE users SET active = true"); err != nil {
+					return err
+				}
+				return tx.Commit()
+			},
+			expected: comment + "UPDATE users SET active = true",
+		},
+		{
+			name:   "custom key",
+			config: Config{Key: "request_id"},
+			run: func(db *sql.DB) error {
+				_, err := db.ExecContext(ctx, "SELECT 1")
+				return err
+			},
+			expected: "/*request_id='test-id'*/ SELECT 1",
+		},
+		{
+			name: "context without ID",
+			run: func(db *sql.DB) error {
+				_, err := db.ExecContext(context.Background(), "SELECT 1")
+				return err
+			},
+			expected: "SELECT 1",
+		},
+		{
+			name:  "driver without ExecerContext",
+			basic: true,
+			run: func(db *sql.DB) error {
+				_, err := db.ExecContext(ctx, "SELECT 1")
+				return err
+			},
+			expected: comment + "SELECT 1",
+		},
+		{
+			name:   "legacy Execer without prepare",
+			legacy: true,
+			config: Config{SkipPrepared: true},
+			run: func(db *sql.DB) error {
+				_, err := db.ExecContext(ctx, "DELETE FROM users WHERE id = ?", 1)
+				return err
+			},
+			expected: comment + "DELETE FROM users WHERE id = ?",
+		},
+		{
+			name:   "legacy Queryer without prepare",
+			legacy: true,
+			config: Config{SkipPrepared: true},
+			run: func(db *sql.DB) error {
+				rows, err := db.QueryContext(ctx, "SELECT 1")
+				if err == nil {
+					err = rows.Close()
+				}
+				return err
+			},
+			expected: comment + "SELECT 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &fakeDriver{basic: tt.basic, legacy: tt.legacy}
+			db := OpenDB(d, tt.config)
+			defer func() { _ = db.Close() }()
+
+			if err := tt.run(db); err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			if got := d.lastQuery(); got != tt.expected {
+				t.Errorf("Query = %q, want %q", got, tt.expected)
+			}
+			if tt.legacy && d.prepared != 0 {
+				t.Errorf("Expected legacy driver not to prepare, got %d prepares", d.prepared)
+			}
+		})
+	}
+}
+
+// closableConnector is a driver.Connector recording whether it was closed
+type closableConnector struct {
+	*fakeDriver
+	closed bool
+}
+
+func (c *closableConnector) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestConnectorClose(t *testing.T) {
+	t.Run("closes closable connector", func(t *testing.T) {
+		base := &closableConnector{fakeDriver: &fakeDriver{}}
+		db := OpenDB(base)
+		if err := db.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		if !base.closed {
+			t.Error("Expected sql.DB.Close to close the wrapped connector")
+		}
+	})
+
+	t.Run("ignores connector without Close", func(t *testing.T) {
+		db := OpenDB(&fakeDriver{})
+		if err := db.Close(); err != nil {
+			t.Errorf("Close failed: %v", err)
+		}
+	})
+}
+
+func TestWrapDriver(t *testing.T) {
+	d := &fakeDriver{}
+	sql.Register("goctxid-fake", WrapDriver(d))
+	db, err := sql.Open("goctxid-fake", "")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+	if _, err := db.ExecContext(ctx, "SELECT 1"); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if got, want := d.lastQuery(), "/*correlation_id='test-id'*/ SELECT 1"; got != want {
+		t.Errorf("Query = %q, want %q", got, want)
+	}
+}
+
+func TestCommentEscaping(t *testing.T) {
+	cfg := configDefault()
+	ctx := goctxid.NewContext(context.Background(), "x'*/ DROP TABLE users; /*")
+
+	got := cfg.comment(ctx, "SELECT 1")
+	comment := strings.TrimSuffix(got, " SELECT 1")
+	if strings.Count(got, "*/") != 1 || strings.Count(comment, "'") != 2 {
+		t.Errorf("ID escaped the comment: %q", got)
+	}
+	if want := "/*correlation_id='x%27%2A%2F%20DROP%20TABLE%20users%3B%20%2F%2A'*/ SELECT 1"; got != want {
+		t.Errorf("comment() = %q, want %q", got, want)
+	}
+}
+
+func BenchmarkComment(b *testing.B) {
+	cfg := configDefault()
+	ctx := goctxid.NewContext(context.Background(), goctxid.DefaultGenerator())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = cfg.comment(ctx, "SELECT * FROM users WHERE id = $1")
+	}
+}
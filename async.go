@@ -0,0 +1,170 @@
+package goctxid
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrPoolClosed is returned by Pool.Submit after Close has been called
+var ErrPoolClosed = errors.New("goctxid: pool is closed")
+
+// Go runs fn in a new goroutine with a detached copy of ctx: it carries the
+// correlation ID (and every other value of ctx) but is not canceled when the
+// request ends, so background work keeps logging the right ID after the
+// handler returns. With Fiber, pass DetachedContext(c) from the adapter
+// instead of c.UserContext() (see Snapshot).
+//
+// Example usage:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    goctxid.Go(r.Context(), func(ctx context.Context) {
+//	        sendEmail(ctx) // goctxid.MustFromContext(ctx) is the request's ID
+//	    })
+//	    w.WriteHeader(http.StatusAccepted)
+//	}
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	ctx = context.WithoutCancel(ctx)
+	go fn(ctx)
+}
+
+// Snapshot returns a new background context carrying only the goctxid values
+// of ctx: the correlation ID and its info, the request ID and the traceparent.
+// Unlike Go, it keeps no reference to ctx, which matters when ctx is tied to
+// an object that gets recycled (e.g. fasthttp's RequestCtx in Fiber).
+func Snapshot(ctx context.Context) context.Context {
+	out := context.Background()
+	if id, ok := FromContext(ctx); ok {
+		out = NewContext(out, id)
+		if info, ok := ctx.Value(infoCtxKey).(IDInfo); ok && info.ID == id {
+			out = context.WithValue(out, infoCtxKey, info)
+		}
+	}
+	if id, ok := RequestIDFromContext(ctx); ok {
+		out = NewRequestIDContext(out, id)
+	}
+	if tp, ok := TraceParentFromContext(ctx); ok {
+		out = NewTraceParentContext(out, tp)
+	}
+	return out
+}
+
+// Group runs a set of goroutines with a detached copy of a request context,
+// like golang.org/x/sync/errgroup. The group context is canceled when a
+// goroutine returns an error or Wait returns, but not when the request ends.
+// A Group must be created with NewGroup.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup returns a Group whose goroutines get a detached copy of ctx
+//
+// Example usage:
+//
+//	g := goctxid.NewGroup(r.Context())
+//	g.Go(func(ctx context.Context) error { return indexDocument(ctx, doc) })
+//	g.Go(func(ctx context.Context) error { return notifySubscribers(ctx, doc) })
+//	if err := g.Wait(); err != nil {
+//	    log.Printf("[%s] %v", goctxid.MustFromContext(r.Context()), err)
+//	}
+func NewGroup(ctx context.Context) *Group {
+	ctx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Go runs fn in a new goroutine. The first error returned by a goroutine
+// cancels the group context and is returned by Wait.
+func (g *Group) Go(fn func(ctx context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := fn(g.ctx); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				g.cancel(err)
+			})
+		}
+	}()
+}
+
+// Wait blocks until all goroutines have returned, then returns the first
+// error (if any)
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel(nil)
+	return g.err
+}
+
+// Pool runs tasks with a detached copy of their context on at most a fixed
+// number of goroutines at a time. A Pool must be created with NewPool and is
+// safe for concurrent use.
+type Pool struct {
+	sem chan struct{}
+	wg  sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewPool returns a Pool running at most workers tasks at a time
+// (at least one)
+//
+// Example usage:
+//
+//	var thumbnails = goctxid.NewPool(8)
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    err := thumbnails.Submit(r.Context(), func(ctx context.Context) {
+//	        resize(ctx, upload) // Logs with the request's ID
+//	    })
+//	    // ...
+//	}
+func NewPool(workers int) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Pool{sem: make(chan struct{}, workers)}
+}
+
+// Submit runs fn with a detached copy of ctx once a worker is free, blocking
+// until then. Returns ctx.Err() if ctx is canceled while waiting (fn is not
+// run) and ErrPoolClosed after Close.
+func (p *Pool) Submit(ctx context.Context, fn func(ctx context.Context)) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrPoolClosed
+	}
+	p.wg.Add(1)
+	p.mu.Unlock()
+
+	select {
+	case p.sem <- struct{}{}:
+	case <-ctx.Done():
+		p.wg.Done()
+		return ctx.Err()
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer func() {
+			<-p.sem
+			p.wg.Done()
+		}()
+		fn(ctx)
+	}()
+	return nil
+}
+
+// Close stops accepting tasks and waits for submitted tasks to finish
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	p.wg.Wait()
+}
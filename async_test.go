@@ -0,0 +1,171 @@
+package goctxid
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGo(t *testing.T) {
+	ctx, cancel := context.WithCancel(NewContext(context.Background(), "request-id"))
+
+	done := make(chan struct{})
+	var gotID string
+	var gotErr error
+	Go(ctx, func(ctx context.Context) {
+		defer close(done)
+		<-time.After(10 * time.Millisecond)
+		gotID, gotErr = MustFromContext(ctx), ctx.Err()
+	})
+	// The handler returns and the request context is canceled
+	cancel()
+	<-done
+
+	if gotID != "request-id" {
+		t.Errorf("ID in goroutine = %v, want request-id", gotID)
+	}
+	if gotErr != nil {
+		t.Errorf("Goroutine context was canceled with the request: %v", gotErr)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	t.Run("carries ID and survives request cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(NewContext(context.Background(), "request-id"))
+		g := NewGroup(ctx)
+		cancel()
+
+		var ids sync.Map
+		for i := 0; i < 10; i++ {
+			g.Go(func(ctx context.Context) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				ids.Store(MustFromContext(ctx), true)
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+		if _, ok := ids.Load("request-id"); !ok {
+			t.Error("Expected goroutines to see the request ID")
+		}
+	})
+
+	t.Run("first error cancels the group", func(t *testing.T) {
+		g := NewGroup(NewContext(context.Background(), "request-id"))
+		errFirst := errors.New("first")
+
+		g.Go(func(ctx context.Context) error { return errFirst })
+		g.Go(func(ctx context.Context) error {
+			<-ctx.Done()
+			if !errors.Is(context.Cause(ctx), errFirst) {
+				t.Errorf("Cause = %v, want %v", context.Cause(ctx), errFirst)
+			}
+			return ctx.Err()
+		})
+
+		if err := g.Wait(); !errors.Is(err, errFirst) {
+			t.Errorf("Wait() error = %v, want %v", err, errFirst)
+		}
+	})
+}
+
+func TestPool(t *testing.T) {
+	t.Run("limits concurrency and carries ID", func(t *testing.T) {
+		const workers = 3
+		p := NewPool(workers)
+
+		ctx, cancel := context.WithCancel(NewContext(context.Background(), "request-id"))
+		var running, maxRunning atomic.Int32
+		var wrongID atomic.Bool
+		for i := 0; i < 20; i++ {
+			err := p.Submit(ctx, func(ctx context.Context) {
+				n := running.Add(1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				if MustFromContext(ctx) != "request-id" || ctx.Err() != nil {
+					wrongID.Store(true)
+				}
+				running.Add(-1)
+			})
+			if err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+		}
+		cancel()
+		p.Close()
+
+		if got := maxRunning.Load(); got > workers {
+			t.Errorf("Max concurrent tasks = %d, want at most %d", got, workers)
+		}
+		if wrongID.Load() {
+			t.Error("Expected tasks to see the request ID in an uncanceled context")
+		}
+	})
+
+	t.Run("returns error when context is canceled while waiting", func(t *testing.T) {
+		p := NewPool(1)
+		defer p.Close()
+
+		release := make(chan struct{})
+		if err := p.Submit(context.Background(), func(context.Context) { <-release }); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := p.Submit(ctx, func(context.Context) { t.Error("Task should not run") })
+		close(release)
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Submit() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("rejects tasks after Close", func(t *testing.T) {
+		p := NewPool(0)
+		p.Close()
+		if err := p.Submit(context.Background(), func(context.Context) {}); !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Submit() error = %v, want %v", err, ErrPoolClosed)
+		}
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	res := Resolution{ID: "request-id", Origin: OriginHeader, HeaderKey: DefaultHeaderKey, RequestID: "local-id", TraceParent: NewTraceParent("request-id")}
+	type otherKey struct{}
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), otherKey{}, "other"))
+	ctx := res.NewContext(parent)
+	cancel()
+
+	snapshot := Snapshot(ctx)
+
+	if err := snapshot.Err(); err != nil {
+		t.Errorf("Snapshot was canceled with its source: %v", err)
+	}
+	if snapshot.Value(otherKey{}) != nil {
+		t.Error("Expected snapshot to only carry goctxid values")
+	}
+	if info, _ := FromContextWithInfo(snapshot); info != res.Info() {
+		t.Errorf("Snapshot info = %+v, want %+v", info, res.Info())
+	}
+	if got := MustRequestIDFromContext(snapshot); got != "local-id" {
+		t.Errorf("Snapshot request ID = %v, want local-id", got)
+	}
+	if tp, _ := TraceParentFromContext(snapshot); tp != res.TraceParent {
+		t.Errorf("Snapshot traceparent = %v, want %v", tp, res.TraceParent)
+	}
+
+	if _, ok := FromContext(Snapshot(context.Background())); ok {
+		t.Error("Expected no ID in snapshot of an empty context")
+	}
+}
@@ -0,0 +1,185 @@
+package goctxid
+
+import (
+	"context"
+	"strings"
+)
+
+// Carrier gives Inject and Extract access to the headers of a message, e.g. a
+// Kafka record or a NATS message. Implementations need not be safe for
+// concurrent use.
+type Carrier interface {
+	// Get returns the value of a header, or an empty string if it is not set
+	Get(key string) string
+
+	// Set sets a header, replacing any existing value
+	Set(key, value string)
+
+	// Keys returns the names of all headers
+	Keys() []string
+}
+
+// CarrierConfig defines the options of Inject and Extract
+type CarrierConfig struct {
+	// HeaderKey is the message header used for the correlation ID. Use the
+	// same value as Config.HeaderKey to keep HTTP and message headers aligned.
+	// (Default: "X-Correlation-ID")
+	HeaderKey string
+}
+
+// carrierConfigDefault is a helper function that merges the provided config with the default config
+func carrierConfigDefault(config ...CarrierConfig) CarrierConfig {
+
+	var cfg CarrierConfig
+
+	// If a config is provided, use it
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Check and fill in default values
+	if cfg.HeaderKey == "" {
+		cfg.HeaderKey = DefaultHeaderKey
+	}
+
+	return cfg
+}
+
+// Inject writes the correlation ID stored in ctx to the carrier under
+// CarrierConfig.HeaderKey (DefaultHeaderKey unless configured). The carrier is
+// left unchanged if ctx carries no ID.
+//
+// Example usage:
+//
+//	headers := goctxid.MapCarrier{}
+//	goctxid.Inject(ctx, headers)
+//	msg := &nats.Msg{Subject: "orders", Header: nats.Header{}}
+//	for key, value := range headers {
+//	    msg.Header.Set(key, value)
+//	}
+func Inject(ctx context.Context, carrier Carrier, config ...CarrierConfig) {
+	if id, ok := FromContext(ctx); ok && id != "" {
+		cfg := carrierConfigDefault(config...)
+		carrier.Set(cfg.HeaderKey, id)
+	}
+}
+
+// Extract returns a context carrying the correlation ID found in the carrier
+// under CarrierConfig.HeaderKey (matched case-insensitively), so consumers can log
+// with the ID of the request that produced the message. The origin is
+// OriginHeader (see FromContextWithInfo). ctx is returned unchanged if the
+// carrier has no ID.
+//
+// The ID is not validated; check it before use if producers are not trusted.
+//
+// Example usage:
+//
+//	var headers goctxid.HeaderSliceCarrier
+//	for _, h := range msg.Headers { // kafka-go message
+//	    headers = append(headers, goctxid.MessageHeader{Key: h.Key, Value: h.Value})
+//	}
+//	ctx := goctxid.Extract(context.Background(), &headers)
+//	process(ctx, msg)
+func Extract(ctx context.Context, carrier Carrier, config ...CarrierConfig) context.Context {
+	cfg := carrierConfigDefault(config...)
+	key, id := cfg.HeaderKey, carrier.Get(cfg.HeaderKey)
+	if id == "" {
+		// Header names are not canonicalized by most message brokers
+		for _, k := range carrier.Keys() {
+			if strings.EqualFold(k, cfg.HeaderKey) {
+				if key, id = k, carrier.Get(k); id != "" {
+					break
+				}
+			}
+		}
+	}
+	if id == "" {
+		return ctx
+	}
+	return Resolution{ID: id, Origin: OriginHeader, HeaderKey: key}.NewContext(ctx)
+}
+
+// MapCarrier is a Carrier over a map of string headers
+type MapCarrier map[string]string
+
+// Get implements Carrier
+func (c MapCarrier) Get(key string) string {
+	return c[key]
+}
+
+// Set implements Carrier
+func (c MapCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys implements Carrier
+func (c MapCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// BytesMapCarrier is a Carrier over a map of []byte headers
+type BytesMapCarrier map[string][]byte
+
+// Get implements Carrier
+func (c BytesMapCarrier) Get(key string) string {
+	return string(c[key])
+}
+
+// Set implements Carrier
+func (c BytesMapCarrier) Set(key, value string) {
+	c[key] = []byte(value)
+}
+
+// Keys implements Carrier
+func (c BytesMapCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// MessageHeader is a single message header, with the same shape as the header
+// types of most Kafka clients (e.g. kafka-go and confluent-kafka-go)
+type MessageHeader struct {
+	Key   string
+	Value []byte
+}
+
+// HeaderSliceCarrier is a Carrier over an ordered list of headers.
+// Use a pointer so Set can append to the slice.
+type HeaderSliceCarrier []MessageHeader
+
+// Get implements Carrier. Returns the first header with the key.
+func (c *HeaderSliceCarrier) Get(key string) string {
+	for _, h := range *c {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set implements Carrier. Replaces the first header with the key, or appends one.
+func (c *HeaderSliceCarrier) Set(key, value string) {
+	for i := range *c {
+		if (*c)[i].Key == key {
+			(*c)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c = append(*c, MessageHeader{Key: key, Value: []byte(value)})
+}
+
+// Keys implements Carrier
+func (c *HeaderSliceCarrier) Keys() []string {
+	keys := make([]string, len(*c))
+	for i, h := range *c {
+		keys[i] = h.Key
+	}
+	return keys
+}
@@ -0,0 +1,153 @@
+package goctxid
+
+import (
+	"context"
+	"sort"
+	"testing"
+)
+
+func TestCarriers(t *testing.T) {
+	carriers := map[string]func() Carrier{
+		"MapCarrier":         func() Carrier { return MapCarrier{} },
+		"BytesMapCarrier":    func() Carrier { return BytesMapCarrier{} },
+		"HeaderSliceCarrier": func() Carrier { return &HeaderSliceCarrier{} },
+	}
+
+	for name, newCarrier := range carriers {
+		t.Run(name, func(t *testing.T) {
+			carrier := newCarrier()
+			if got := carrier.Get("missing"); got != "" {
+				t.Errorf("Get(missing) = %v, want empty", got)
+			}
+
+			carrier.Set("a", "1")
+			carrier.Set("b", "2")
+			carrier.Set("a", "3")
+			if got := carrier.Get("a"); got != "3" {
+				t.Errorf("Get(a) = %v, want 3", got)
+			}
+
+			keys := carrier.Keys()
+			sort.Strings(keys)
+			if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+				t.Errorf("Keys() = %v, want [a b]", keys)
+			}
+		})
+	}
+
+	t.Run("HeaderSliceCarrier keeps existing headers", func(t *testing.T) {
+		headers := HeaderSliceCarrier{{Key: "content-type", Value: []byte("application/json")}}
+		Inject(NewContext(context.Background(), "ctx-id"), &headers)
+
+		if len(headers) != 2 || headers[0].Key != "content-type" {
+			t.Fatalf("Expected ID to be appended, got %v", headers)
+		}
+		if got := string(headers[1].Value); got != "ctx-id" {
+			t.Errorf("Injected ID = %v, want ctx-id", got)
+		}
+	})
+}
+
+func TestInject(t *testing.T) {
+	t.Run("writes ID from context", func(t *testing.T) {
+		carrier := MapCarrier{}
+		Inject(NewContext(context.Background(), "ctx-id"), carrier)
+		if got := carrier[DefaultHeaderKey]; got != "ctx-id" {
+			t.Errorf("Injected ID = %v, want ctx-id", got)
+		}
+	})
+
+	t.Run("leaves carrier unchanged without ID", func(t *testing.T) {
+		carrier := MapCarrier{}
+		Inject(context.Background(), carrier)
+		if len(carrier) != 0 {
+			t.Errorf("Expected empty carrier, got %v", carrier)
+		}
+	})
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name              string
+		carrier           Carrier
+		expectedID        string
+		expectedHeaderKey string
+	}{
+		{
+			name:              "reads DefaultHeaderKey",
+			carrier:           MapCarrier{DefaultHeaderKey: "msg-id"},
+			expectedID:        "msg-id",
+			expectedHeaderKey: DefaultHeaderKey,
+		},
+		{
+			name:              "matches header key case-insensitively",
+			carrier:           BytesMapCarrier{"x-correlation-id": []byte("msg-id")},
+			expectedID:        "msg-id",
+			expectedHeaderKey: "x-correlation-id",
+		},
+		{
+			name:              "skips empty headers",
+			carrier:           &HeaderSliceCarrier{{Key: "X-CORRELATION-ID"}, {Key: "x-correlation-id", Value: []byte("msg-id")}},
+			expectedID:        "msg-id",
+			expectedHeaderKey: "x-correlation-id",
+		},
+		{
+			name:       "returns context unchanged without ID",
+			carrier:    MapCarrier{"other": "value"},
+			expectedID: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := Extract(context.Background(), tt.carrier)
+
+			info, ok := FromContextWithInfo(ctx)
+			if tt.expectedID == "" {
+				if ok {
+					t.Errorf("Expected no ID in context, got %v", info)
+				}
+				return
+			}
+			if info.ID != tt.expectedID {
+				t.Errorf("Extracted ID = %v, want %v", info.ID, tt.expectedID)
+			}
+			if info.Origin != OriginHeader || info.HeaderKey != tt.expectedHeaderKey {
+				t.Errorf("Extracted info = %+v, want origin %v and header key %v", info, OriginHeader, tt.expectedHeaderKey)
+			}
+		})
+	}
+
+	t.Run("custom header key", func(t *testing.T) {
+		cfg := CarrierConfig{HeaderKey: "X-Request-ID"}
+		carrier := MapCarrier{}
+		Inject(NewContext(context.Background(), "ctx-id"), carrier, cfg)
+		if got := carrier["X-Request-ID"]; got != "ctx-id" || len(carrier) != 1 {
+			t.Fatalf("Injected headers = %v, want only X-Request-ID", carrier)
+		}
+
+		info, _ := FromContextWithInfo(Extract(context.Background(), BytesMapCarrier{"x-request-id": []byte("msg-id")}, cfg))
+		if info.ID != "msg-id" || info.HeaderKey != "x-request-id" {
+			t.Errorf("Extracted info = %+v, want msg-id from x-request-id", info)
+		}
+		if _, ok := FromContext(Extract(context.Background(), MapCarrier{DefaultHeaderKey: "msg-id"}, cfg)); ok {
+			t.Error("Expected DefaultHeaderKey to be ignored with a custom key")
+		}
+	})
+
+	t.Run("empty config uses default key", func(t *testing.T) {
+		carrier := MapCarrier{}
+		Inject(NewContext(context.Background(), "ctx-id"), carrier, CarrierConfig{})
+		if got := MustFromContext(Extract(context.Background(), carrier, CarrierConfig{})); got != "ctx-id" || carrier[DefaultHeaderKey] != "ctx-id" {
+			t.Errorf("Round trip = %v (headers %v), want ctx-id under %s", got, carrier, DefaultHeaderKey)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		var headers HeaderSliceCarrier
+		Inject(NewContext(context.Background(), "ctx-id"), &headers)
+		if got := MustFromContext(Extract(context.Background(), &headers)); got != "ctx-id" {
+			t.Errorf("Round trip ID = %v, want ctx-id", got)
+		}
+	})
+}
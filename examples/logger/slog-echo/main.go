@@ -6,16 +6,19 @@ import (
 	"os"
 	"time"
 
-	"github.com/hiiamtin/goctxid"
 	goctxid_echo "github.com/hiiamtin/goctxid/adapters/echo"
+	"github.com/hiiamtin/goctxid/slogctx"
 	"github.com/labstack/echo/v4"
 )
 
-var logger *slog.Logger
+var (
+	logger    *slog.Logger // HTTP access logs
+	appLogger *slog.Logger // Application logs
+)
 
 func main() {
 	// Initialize slog logger
-	logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	setupLogger(os.Stdout)
 
 	e := echo.New()
 	e.HideBanner = true
@@ -41,21 +44,20 @@ func slogMiddleware() echo.MiddlewareFunc {
 
 			err := next(c)
 
-			correlationID := goctxid.MustFromContext(c.Request().Context())
+			ctx := c.Request().Context()
 			latency := time.Since(start)
 
-			// HTTP Access Log
+			// HTTP Access Log (slogctx adds the correlation ID from ctx)
 			status := c.Response().Status
-			logFunc := logger.Info
+			level := slog.LevelInfo
 			if status >= 500 {
-				logFunc = logger.Error
+				level = slog.LevelError
 			} else if status >= 400 {
-				logFunc = logger.Warn
+				level = slog.LevelWarn
 			}
 
-			logFunc("HTTP Request",
+			logger.Log(ctx, level, "HTTP Request",
 				"type", "http_access",
-				"correlation_id", correlationID,
 				"method", c.Request().Method,
 				"path", c.Path(),
 				"query", c.QueryString(),
@@ -71,24 +73,15 @@ func slogMiddleware() echo.MiddlewareFunc {
 	}
 }
 
-// Helper to get logger with correlation ID
-func getLogger(c echo.Context) *slog.Logger {
-	correlationID := goctxid.MustFromContext(c.Request().Context())
-	return logger.With(
-		"correlation_id", correlationID,
-		"type", "application",
-	)
-}
-
 func healthCheck(c echo.Context) error {
 	return c.JSON(200, map[string]string{"status": "ok"})
 }
 
 func getUser(c echo.Context) error {
-	log := getLogger(c)
+	ctx := c.Request().Context()
 	userID := c.Param("id")
 
-	log.Info("Fetching user from database", "user_id", userID)
+	appLogger.InfoContext(ctx, "Fetching user from database", "user_id", userID)
 
 	// Simulate database call
 	time.Sleep(10 * time.Millisecond)
@@ -97,34 +90,41 @@ func getUser(c echo.Context) error {
 		"name": "John Doe",
 	}
 
-	log.Info("User fetched successfully", "user_id", userID)
+	appLogger.InfoContext(ctx, "User fetched successfully", "user_id", userID)
 	return c.JSON(200, user)
 }
 
 func createUser(c echo.Context) error {
-	log := getLogger(c)
+	ctx := c.Request().Context()
 
 	var input struct {
 		Name string `json:"name"`
 	}
 
 	if err := c.Bind(&input); err != nil {
-		log.Error("Invalid request body", "error", err)
+		appLogger.ErrorContext(ctx, "Invalid request body", "error", err)
 		return c.JSON(400, map[string]string{"error": "Invalid request"})
 	}
 
-	log.Info("Creating new user", "name", input.Name)
+	appLogger.InfoContext(ctx, "Creating new user", "name", input.Name)
 
 	// Simulate database insert
 	time.Sleep(20 * time.Millisecond)
 	newUserID := 123
 
-	log.Info("User created successfully", "user_id", newUserID)
+	appLogger.InfoContext(ctx, "User created successfully", "user_id", newUserID)
 	return c.JSON(201, map[string]interface{}{"id": newUserID})
 }
 
+// setupLogger creates the loggers writing JSON to w. slogctx adds the
+// correlation ID from the context passed to InfoContext, ErrorContext, ...
+func setupLogger(w io.Writer) {
+	logger = slog.New(slogctx.NewHandler(slog.NewJSONHandler(w, nil)))
+	appLogger = logger.With("type", "application")
+}
+
 // SetupBenchmarkLogger configures logger to write to discard for benchmarks
 func SetupBenchmarkLogger() {
-	logger = slog.New(slog.NewJSONHandler(io.Discard, nil))
+	setupLogger(io.Discard)
 }
 
@@ -7,15 +7,18 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/hiiamtin/goctxid"
 	goctxid_fiber "github.com/hiiamtin/goctxid/adapters/fiber"
+	"github.com/hiiamtin/goctxid/slogctx"
 )
 
-var logger *slog.Logger
+var (
+	logger    *slog.Logger // HTTP access logs
+	appLogger *slog.Logger // Application logs
+)
 
 func main() {
 	// Initialize slog logger
-	logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	setupLogger(os.Stdout)
 
 	app := fiber.New(fiber.Config{
 		DisableStartupMessage: true,
@@ -41,21 +44,20 @@ func slogMiddleware() fiber.Handler {
 
 		err := c.Next()
 
-		correlationID := goctxid.MustFromContext(c.UserContext())
+		ctx := c.UserContext()
 		latency := time.Since(start)
 
-		// HTTP Access Log
+		// HTTP Access Log (slogctx adds the correlation ID from ctx)
 		status := c.Response().StatusCode()
-		logFunc := logger.Info
+		level := slog.LevelInfo
 		if status >= 500 {
-			logFunc = logger.Error
+			level = slog.LevelError
 		} else if status >= 400 {
-			logFunc = logger.Warn
+			level = slog.LevelWarn
 		}
 
-		logFunc("HTTP Request",
+		logger.Log(ctx, level, "HTTP Request",
 			"type", "http_access",
-			"correlation_id", correlationID,
 			"method", c.Method(),
 			"path", c.Path(),
 			"query", string(c.Request().URI().QueryString()),
@@ -70,24 +72,15 @@ func slogMiddleware() fiber.Handler {
 	}
 }
 
-// Helper to get logger with correlation ID
-func getLogger(c *fiber.Ctx) *slog.Logger {
-	correlationID := goctxid.MustFromContext(c.UserContext())
-	return logger.With(
-		"correlation_id", correlationID,
-		"type", "application",
-	)
-}
-
 func healthCheck(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"status": "ok"})
 }
 
 func getUser(c *fiber.Ctx) error {
-	log := getLogger(c)
+	ctx := c.UserContext()
 	userID := c.Params("id")
 
-	log.Info("Fetching user from database", "user_id", userID)
+	appLogger.InfoContext(ctx, "Fetching user from database", "user_id", userID)
 
 	// Simulate database call
 	time.Sleep(10 * time.Millisecond)
@@ -96,34 +89,41 @@ func getUser(c *fiber.Ctx) error {
 		"name": "John Doe",
 	}
 
-	log.Info("User fetched successfully", "user_id", userID)
+	appLogger.InfoContext(ctx, "User fetched successfully", "user_id", userID)
 	return c.JSON(user)
 }
 
 func createUser(c *fiber.Ctx) error {
-	log := getLogger(c)
+	ctx := c.UserContext()
 
 	var input struct {
 		Name string `json:"name"`
 	}
 
 	if err := c.BodyParser(&input); err != nil {
-		log.Error("Invalid request body", "error", err)
+		appLogger.ErrorContext(ctx, "Invalid request body", "error", err)
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid request"})
 	}
 
-	log.Info("Creating new user", "name", input.Name)
+	appLogger.InfoContext(ctx, "Creating new user", "name", input.Name)
 
 	// Simulate database insert
 	time.Sleep(20 * time.Millisecond)
 	newUserID := 123
 
-	log.Info("User created successfully", "user_id", newUserID)
+	appLogger.InfoContext(ctx, "User created successfully", "user_id", newUserID)
 	return c.Status(201).JSON(fiber.Map{"id": newUserID})
 }
 
+// setupLogger creates the loggers writing JSON to w. slogctx adds the
+// correlation ID from the context passed to InfoContext, ErrorContext, ...
+func setupLogger(w io.Writer) {
+	logger = slog.New(slogctx.NewHandler(slog.NewJSONHandler(w, nil)))
+	appLogger = logger.With("type", "application")
+}
+
 // SetupBenchmarkLogger configures logger to write to discard for benchmarks
 func SetupBenchmarkLogger() {
-	logger = slog.New(slog.NewJSONHandler(io.Discard, nil))
+	setupLogger(io.Discard)
 }
 
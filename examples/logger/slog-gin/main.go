@@ -7,15 +7,18 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/hiiamtin/goctxid"
 	goctxid_gin "github.com/hiiamtin/goctxid/adapters/gin"
+	"github.com/hiiamtin/goctxid/slogctx"
 )
 
-var logger *slog.Logger
+var (
+	logger    *slog.Logger // HTTP access logs
+	appLogger *slog.Logger // Application logs
+)
 
 func main() {
 	// Initialize slog logger
-	logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	setupLogger(os.Stdout)
 
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
@@ -41,21 +44,20 @@ func slogMiddleware() gin.HandlerFunc {
 
 		c.Next()
 
-		correlationID := goctxid.MustFromContext(c.Request.Context())
+		ctx := c.Request.Context()
 		latency := time.Since(start)
 
-		// HTTP Access Log
+		// HTTP Access Log (slogctx adds the correlation ID from ctx)
 		status := c.Writer.Status()
-		logFunc := logger.Info
+		level := slog.LevelInfo
 		if status >= 500 {
-			logFunc = logger.Error
+			level = slog.LevelError
 		} else if status >= 400 {
-			logFunc = logger.Warn
+			level = slog.LevelWarn
 		}
 
-		logFunc("HTTP Request",
+		logger.Log(ctx, level, "HTTP Request",
 			"type", "http_access",
-			"correlation_id", correlationID,
 			"method", c.Request.Method,
 			"path", c.Request.URL.Path,
 			"query", c.Request.URL.RawQuery,
@@ -68,24 +70,15 @@ func slogMiddleware() gin.HandlerFunc {
 	}
 }
 
-// Helper to get logger with correlation ID
-func getLogger(c *gin.Context) *slog.Logger {
-	correlationID := goctxid.MustFromContext(c.Request.Context())
-	return logger.With(
-		"correlation_id", correlationID,
-		"type", "application",
-	)
-}
-
 func healthCheck(c *gin.Context) {
 	c.JSON(200, gin.H{"status": "ok"})
 }
 
 func getUser(c *gin.Context) {
-	log := getLogger(c)
+	ctx := c.Request.Context()
 	userID := c.Param("id")
 
-	log.Info("Fetching user from database", "user_id", userID)
+	appLogger.InfoContext(ctx, "Fetching user from database", "user_id", userID)
 
 	// Simulate database call
 	time.Sleep(10 * time.Millisecond)
@@ -94,35 +87,42 @@ func getUser(c *gin.Context) {
 		"name": "John Doe",
 	}
 
-	log.Info("User fetched successfully", "user_id", userID)
+	appLogger.InfoContext(ctx, "User fetched successfully", "user_id", userID)
 	c.JSON(200, user)
 }
 
 func createUser(c *gin.Context) {
-	log := getLogger(c)
+	ctx := c.Request.Context()
 
 	var input struct {
 		Name string `json:"name"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		log.Error("Invalid request body", "error", err)
+		appLogger.ErrorContext(ctx, "Invalid request body", "error", err)
 		c.JSON(400, gin.H{"error": "Invalid request"})
 		return
 	}
 
-	log.Info("Creating new user", "name", input.Name)
+	appLogger.InfoContext(ctx, "Creating new user", "name", input.Name)
 
 	// Simulate database insert
 	time.Sleep(20 * time.Millisecond)
 	newUserID := 123
 
-	log.Info("User created successfully", "user_id", newUserID)
+	appLogger.InfoContext(ctx, "User created successfully", "user_id", newUserID)
 	c.JSON(201, gin.H{"id": newUserID})
 }
 
+// setupLogger creates the loggers writing JSON to w. slogctx adds the
+// correlation ID from the context passed to InfoContext, ErrorContext, ...
+func setupLogger(w io.Writer) {
+	logger = slog.New(slogctx.NewHandler(slog.NewJSONHandler(w, nil)))
+	appLogger = logger.With("type", "application")
+}
+
 // SetupBenchmarkLogger configures logger to write to discard for benchmarks
 func SetupBenchmarkLogger() {
-	logger = slog.New(slog.NewJSONHandler(io.Discard, nil))
+	setupLogger(io.Discard)
 }
 
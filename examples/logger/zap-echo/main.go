@@ -4,14 +4,17 @@ import (
 	"io"
 	"time"
 
-	"github.com/hiiamtin/goctxid"
 	goctxid_echo "github.com/hiiamtin/goctxid/adapters/echo"
+	"github.com/hiiamtin/goctxid/zapctx"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var logger *zap.Logger
+var (
+	logger    *zap.Logger // HTTP access logs
+	appLogger *zap.Logger // Application logs
+)
 
 func main() {
 	// Initialize Zap logger
@@ -24,6 +27,7 @@ func main() {
 		panic(err)
 	}
 	defer logger.Sync()
+	appLogger = logger.With(zap.String("type", "application"))
 
 	e := echo.New()
 	e.HideBanner = true
@@ -49,14 +53,13 @@ func zapMiddleware() echo.MiddlewareFunc {
 
 			err := next(c)
 
-			correlationID := goctxid.MustFromContext(c.Request().Context())
+			log := zapctx.Logger(c.Request().Context(), logger)
 			latency := time.Since(start)
 
 			// HTTP Access Log
 			status := c.Response().Status
 			fields := []zap.Field{
 				zap.String("type", "http_access"),
-				zap.String("correlation_id", correlationID),
 				zap.String("method", c.Request().Method),
 				zap.String("path", c.Path()),
 				zap.String("query", c.QueryString()),
@@ -68,11 +71,11 @@ func zapMiddleware() echo.MiddlewareFunc {
 			}
 
 			if status >= 500 {
-				logger.Error("HTTP Request", fields...)
+				log.Error("HTTP Request", fields...)
 			} else if status >= 400 {
-				logger.Warn("HTTP Request", fields...)
+				log.Warn("HTTP Request", fields...)
 			} else {
-				logger.Info("HTTP Request", fields...)
+				log.Info("HTTP Request", fields...)
 			}
 
 			return err
@@ -82,11 +85,7 @@ func zapMiddleware() echo.MiddlewareFunc {
 
 // Helper to get logger with correlation ID
 func getLogger(c echo.Context) *zap.Logger {
-	correlationID := goctxid.MustFromContext(c.Request().Context())
-	return logger.With(
-		zap.String("correlation_id", correlationID),
-		zap.String("type", "application"),
-	)
+	return zapctx.Logger(c.Request().Context(), appLogger)
 }
 
 func healthCheck(c echo.Context) error {
@@ -154,5 +153,6 @@ func SetupBenchmarkLogger() {
 		zapcore.InfoLevel,
 	)
 	logger = zap.New(core)
+	appLogger = logger.With(zap.String("type", "application"))
 }
 
@@ -5,13 +5,16 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/hiiamtin/goctxid"
 	goctxid_fiber "github.com/hiiamtin/goctxid/adapters/fiber"
+	"github.com/hiiamtin/goctxid/zapctx"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var logger *zap.Logger
+var (
+	logger    *zap.Logger // HTTP access logs
+	appLogger *zap.Logger // Application logs
+)
 
 func main() {
 	// Initialize Zap logger
@@ -24,6 +27,7 @@ func main() {
 		panic(err)
 	}
 	defer logger.Sync()
+	appLogger = logger.With(zap.String("type", "application"))
 
 	app := fiber.New(fiber.Config{
 		DisableStartupMessage: true,
@@ -49,13 +53,12 @@ func zapMiddleware() fiber.Handler {
 
 		err := c.Next()
 
-		correlationID := goctxid.MustFromContext(c.UserContext())
+		log := zapctx.Logger(c.UserContext(), logger)
 		latency := time.Since(start)
 
 		// HTTP Access Log
 		fields := []zap.Field{
 			zap.String("type", "http_access"),
-			zap.String("correlation_id", correlationID),
 			zap.String("method", c.Method()),
 			zap.String("path", c.Path()),
 			zap.String("query", string(c.Request().URI().QueryString())),
@@ -67,11 +70,11 @@ func zapMiddleware() fiber.Handler {
 		}
 
 		if c.Response().StatusCode() >= 500 {
-			logger.Error("HTTP Request", fields...)
+			log.Error("HTTP Request", fields...)
 		} else if c.Response().StatusCode() >= 400 {
-			logger.Warn("HTTP Request", fields...)
+			log.Warn("HTTP Request", fields...)
 		} else {
-			logger.Info("HTTP Request", fields...)
+			log.Info("HTTP Request", fields...)
 		}
 
 		return err
@@ -80,11 +83,7 @@ func zapMiddleware() fiber.Handler {
 
 // Helper to get logger with correlation ID
 func getLogger(c *fiber.Ctx) *zap.Logger {
-	correlationID := goctxid.MustFromContext(c.UserContext())
-	return logger.With(
-		zap.String("correlation_id", correlationID),
-		zap.String("type", "application"),
-	)
+	return zapctx.Logger(c.UserContext(), appLogger)
 }
 
 func healthCheck(c *fiber.Ctx) error {
@@ -152,4 +151,5 @@ func SetupBenchmarkLogger() {
 		zapcore.InfoLevel,
 	)
 	logger = zap.New(core)
+	appLogger = logger.With(zap.String("type", "application"))
 }
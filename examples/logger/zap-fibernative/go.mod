@@ -7,7 +7,7 @@ replace github.com/hiiamtin/goctxid => ../../..
 require (
 	github.com/gofiber/fiber/v2 v2.52.9
 	github.com/hiiamtin/goctxid v0.0.0-00010101000000-000000000000
-	go.uber.org/zap v1.26.0
+	go.uber.org/zap v1.27.0
 )
 
 require (
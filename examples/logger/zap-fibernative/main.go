@@ -6,11 +6,15 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	goctxid_fibernative "github.com/hiiamtin/goctxid/adapters/fibernative"
+	"github.com/hiiamtin/goctxid/zapctx"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var logger *zap.Logger
+var (
+	logger    *zap.Logger // HTTP access logs
+	appLogger *zap.Logger // Application logs
+)
 
 func main() {
 	// Initialize Zap logger
@@ -23,6 +27,7 @@ func main() {
 		panic(err)
 	}
 	defer logger.Sync()
+	appLogger = logger.With(zap.String("type", "application"))
 
 	app := fiber.New(fiber.Config{
 		DisableStartupMessage: true,
@@ -55,7 +60,7 @@ func zapMiddleware() fiber.Handler {
 		// HTTP Access Log
 		fields := []zap.Field{
 			zap.String("type", "http_access"),
-			zap.String("correlation_id", correlationID),
+			zapctx.Field(correlationID),
 			zap.String("method", c.Method()),
 			zap.String("path", c.Path()),
 			zap.String("query", string(c.Request().URI().QueryString())),
@@ -81,11 +86,7 @@ func zapMiddleware() fiber.Handler {
 // Helper to get logger with correlation ID
 func getLogger(c *fiber.Ctx) *zap.Logger {
 	// Get correlation ID from Locals (Fibernative approach)
-	correlationID := goctxid_fibernative.MustFromLocals(c)
-	return logger.With(
-		zap.String("correlation_id", correlationID),
-		zap.String("type", "application"),
-	)
+	return appLogger.With(zapctx.Field(goctxid_fibernative.MustFromLocals(c)))
 }
 
 func healthCheck(c *fiber.Ctx) error {
@@ -153,4 +154,5 @@ func SetupBenchmarkLogger() {
 		zapcore.InfoLevel,
 	)
 	logger = zap.New(core)
+	appLogger = logger.With(zap.String("type", "application"))
 }
@@ -5,13 +5,16 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/hiiamtin/goctxid"
 	goctxid_gin "github.com/hiiamtin/goctxid/adapters/gin"
+	"github.com/hiiamtin/goctxid/zapctx"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var logger *zap.Logger
+var (
+	logger    *zap.Logger // HTTP access logs
+	appLogger *zap.Logger // Application logs
+)
 
 func main() {
 	// Initialize Zap logger
@@ -24,6 +27,7 @@ func main() {
 		panic(err)
 	}
 	defer logger.Sync()
+	appLogger = logger.With(zap.String("type", "application"))
 
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
@@ -51,13 +55,12 @@ func zapMiddleware() gin.HandlerFunc {
 
 		c.Next()
 
-		correlationID := goctxid.MustFromContext(c.Request.Context())
+		log := zapctx.Logger(c.Request.Context(), logger)
 		latency := time.Since(start)
 
 		// HTTP Access Log
 		fields := []zap.Field{
 			zap.String("type", "http_access"),
-			zap.String("correlation_id", correlationID),
 			zap.String("method", c.Request.Method),
 			zap.String("path", path),
 			zap.String("query", query),
@@ -69,22 +72,18 @@ func zapMiddleware() gin.HandlerFunc {
 		}
 
 		if c.Writer.Status() >= 500 {
-			logger.Error("HTTP Request", fields...)
+			log.Error("HTTP Request", fields...)
 		} else if c.Writer.Status() >= 400 {
-			logger.Warn("HTTP Request", fields...)
+			log.Warn("HTTP Request", fields...)
 		} else {
-			logger.Info("HTTP Request", fields...)
+			log.Info("HTTP Request", fields...)
 		}
 	}
 }
 
 // Helper to get logger with correlation ID
 func getLogger(c *gin.Context) *zap.Logger {
-	correlationID := goctxid.MustFromContext(c.Request.Context())
-	return logger.With(
-		zap.String("correlation_id", correlationID),
-		zap.String("type", "application"),
-	)
+	return zapctx.Logger(c.Request.Context(), appLogger)
 }
 
 func healthCheck(c *gin.Context) {
@@ -153,4 +152,5 @@ func SetupBenchmarkLogger() {
 		zapcore.InfoLevel,
 	)
 	logger = zap.New(core)
+	appLogger = logger.With(zap.String("type", "application"))
 }
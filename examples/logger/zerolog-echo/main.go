@@ -5,17 +5,21 @@ import (
 	"os"
 	"time"
 
-	"github.com/hiiamtin/goctxid"
 	goctxid_echo "github.com/hiiamtin/goctxid/adapters/echo"
+	"github.com/hiiamtin/goctxid/zerologctx"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
+// appLogger is the base logger of application logs
+var appLogger zerolog.Logger
+
 func main() {
 	// Initialize Zerolog
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	log.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+	log.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger().Hook(zerologctx.NewHook())
+	appLogger = log.With().Str("type", "application").Logger()
 
 	e := echo.New()
 	e.HideBanner = true
@@ -39,12 +43,15 @@ func zerologMiddleware() echo.MiddlewareFunc {
 		return func(c echo.Context) error {
 			start := time.Now()
 
+			// Attach a logger carrying the correlation ID for the handlers (see getLogger)
+			c.SetRequest(c.Request().WithContext(zerologctx.WithContext(c.Request().Context(), appLogger)))
+
 			err := next(c)
 
-			correlationID := goctxid.MustFromContext(c.Request().Context())
+			ctx := c.Request().Context()
 			latency := time.Since(start)
 
-			// HTTP Access Log
+			// HTTP Access Log (the hook adds the correlation ID from ctx)
 			status := c.Response().Status
 			event := log.Info()
 			if status >= 500 {
@@ -54,8 +61,8 @@ func zerologMiddleware() echo.MiddlewareFunc {
 			}
 
 			event.
+				Ctx(ctx).
 				Str("type", "http_access").
-				Str("correlation_id", correlationID).
 				Str("method", c.Request().Method).
 				Str("path", c.Path()).
 				Str("query", c.QueryString()).
@@ -71,13 +78,9 @@ func zerologMiddleware() echo.MiddlewareFunc {
 	}
 }
 
-// Helper to get logger with correlation ID
-func getLogger(c echo.Context) zerolog.Logger {
-	correlationID := goctxid.MustFromContext(c.Request().Context())
-	return log.With().
-		Str("correlation_id", correlationID).
-		Str("type", "application").
-		Logger()
+// Helper to get the logger attached by zerologMiddleware
+func getLogger(c echo.Context) *zerolog.Logger {
+	return zerolog.Ctx(c.Request().Context())
 }
 
 func healthCheck(c echo.Context) error {
@@ -125,5 +128,6 @@ func createUser(c echo.Context) error {
 
 // SetupBenchmarkLogger configures logger to write to discard for benchmarks
 func SetupBenchmarkLogger() {
-	log.Logger = zerolog.New(io.Discard).With().Timestamp().Logger()
+	log.Logger = zerolog.New(io.Discard).With().Timestamp().Logger().Hook(zerologctx.NewHook())
+	appLogger = log.With().Str("type", "application").Logger()
 }
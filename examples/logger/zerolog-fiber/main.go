@@ -6,16 +6,20 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/hiiamtin/goctxid"
 	goctxid_fiber "github.com/hiiamtin/goctxid/adapters/fiber"
+	"github.com/hiiamtin/goctxid/zerologctx"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
+// appLogger is the base logger of application logs
+var appLogger zerolog.Logger
+
 func main() {
 	// Initialize Zerolog
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	log.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+	log.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger().Hook(zerologctx.NewHook())
+	appLogger = log.With().Str("type", "application").Logger()
 
 	app := fiber.New(fiber.Config{
 		DisableStartupMessage: true,
@@ -39,12 +43,15 @@ func zerologMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
 
+		// Attach a logger carrying the correlation ID for the handlers (see getLogger)
+		c.SetUserContext(zerologctx.WithContext(c.UserContext(), appLogger))
+
 		err := c.Next()
 
-		correlationID := goctxid.MustFromContext(c.UserContext())
+		ctx := c.UserContext()
 		latency := time.Since(start)
 
-		// HTTP Access Log
+		// HTTP Access Log (the hook adds the correlation ID from ctx)
 		event := log.Info()
 		if c.Response().StatusCode() >= 500 {
 			event = log.Error()
@@ -53,8 +60,8 @@ func zerologMiddleware() fiber.Handler {
 		}
 
 		event.
+			Ctx(ctx).
 			Str("type", "http_access").
-			Str("correlation_id", correlationID).
 			Str("method", c.Method()).
 			Str("path", c.Path()).
 			Str("query", string(c.Request().URI().QueryString())).
@@ -69,13 +76,9 @@ func zerologMiddleware() fiber.Handler {
 	}
 }
 
-// Helper to get logger with correlation ID
-func getLogger(c *fiber.Ctx) zerolog.Logger {
-	correlationID := goctxid.MustFromContext(c.UserContext())
-	return log.With().
-		Str("correlation_id", correlationID).
-		Str("type", "application").
-		Logger()
+// Helper to get the logger attached by zerologMiddleware
+func getLogger(c *fiber.Ctx) *zerolog.Logger {
+	return zerolog.Ctx(c.UserContext())
 }
 
 func healthCheck(c *fiber.Ctx) error {
@@ -123,5 +126,6 @@ func createUser(c *fiber.Ctx) error {
 
 // SetupBenchmarkLogger configures logger to write to discard for benchmarks
 func SetupBenchmarkLogger() {
-	log.Logger = zerolog.New(io.Discard).With().Timestamp().Logger()
+	log.Logger = zerolog.New(io.Discard).With().Timestamp().Logger().Hook(zerologctx.NewHook())
+	appLogger = log.With().Str("type", "application").Logger()
 }
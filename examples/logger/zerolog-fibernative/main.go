@@ -7,14 +7,19 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	goctxid_fibernative "github.com/hiiamtin/goctxid/adapters/fibernative"
+	"github.com/hiiamtin/goctxid/zerologctx"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
+// appLogger is the base logger of application logs
+var appLogger zerolog.Logger
+
 func main() {
 	// Initialize Zerolog
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 	log.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+	appLogger = log.With().Str("type", "application").Logger()
 
 	app := fiber.New(fiber.Config{
 		DisableStartupMessage: true,
@@ -53,7 +58,7 @@ func zerologMiddleware() fiber.Handler {
 
 		event.
 			Str("type", "http_access").
-			Str("correlation_id", correlationID).
+			Str(zerologctx.DefaultKey, correlationID).
 			Str("method", c.Method()).
 			Str("path", c.Path()).
 			Str("query", string(c.Request().URI().QueryString())).
@@ -70,11 +75,8 @@ func zerologMiddleware() fiber.Handler {
 
 // Helper to get logger with correlation ID
 func getLogger(c *fiber.Ctx) zerolog.Logger {
-	correlationID := goctxid_fibernative.MustFromLocals(c)
-	return log.With().
-		Str("correlation_id", correlationID).
-		Str("type", "application").
-		Logger()
+	// Get correlation ID from Locals (Fibernative approach)
+	return zerologctx.WithID(appLogger, goctxid_fibernative.MustFromLocals(c))
 }
 
 func healthCheck(c *fiber.Ctx) error {
@@ -123,4 +125,5 @@ func createUser(c *fiber.Ctx) error {
 // SetupBenchmarkLogger configures logger to write to discard for benchmarks
 func SetupBenchmarkLogger() {
 	log.Logger = zerolog.New(io.Discard).With().Timestamp().Logger()
+	appLogger = log.With().Str("type", "application").Logger()
 }
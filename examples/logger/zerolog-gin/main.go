@@ -6,16 +6,20 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/hiiamtin/goctxid"
 	goctxid_gin "github.com/hiiamtin/goctxid/adapters/gin"
+	"github.com/hiiamtin/goctxid/zerologctx"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
+// appLogger is the base logger of application logs
+var appLogger zerolog.Logger
+
 func main() {
 	// Initialize Zerolog
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	log.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+	log.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger().Hook(zerologctx.NewHook())
+	appLogger = log.With().Str("type", "application").Logger()
 
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
@@ -41,12 +45,15 @@ func zerologMiddleware() gin.HandlerFunc {
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
 
+		// Attach a logger carrying the correlation ID for the handlers (see getLogger)
+		c.Request = c.Request.WithContext(zerologctx.WithContext(c.Request.Context(), appLogger))
+
 		c.Next()
 
-		correlationID := goctxid.MustFromContext(c.Request.Context())
+		ctx := c.Request.Context()
 		latency := time.Since(start)
 
-		// HTTP Access Log
+		// HTTP Access Log (the hook adds the correlation ID from ctx)
 		event := log.Info()
 		if c.Writer.Status() >= 500 {
 			event = log.Error()
@@ -55,8 +62,8 @@ func zerologMiddleware() gin.HandlerFunc {
 		}
 
 		event.
+			Ctx(ctx).
 			Str("type", "http_access").
-			Str("correlation_id", correlationID).
 			Str("method", c.Request.Method).
 			Str("path", path).
 			Str("query", query).
@@ -69,13 +76,9 @@ func zerologMiddleware() gin.HandlerFunc {
 	}
 }
 
-// Helper to get logger with correlation ID
-func getLogger(c *gin.Context) zerolog.Logger {
-	correlationID := goctxid.MustFromContext(c.Request.Context())
-	return log.With().
-		Str("correlation_id", correlationID).
-		Str("type", "application").
-		Logger()
+// Helper to get the logger attached by zerologMiddleware
+func getLogger(c *gin.Context) *zerolog.Logger {
+	return zerolog.Ctx(c.Request.Context())
 }
 
 func healthCheck(c *gin.Context) {
@@ -124,5 +127,6 @@ func createUser(c *gin.Context) {
 
 // SetupBenchmarkLogger configures logger to write to discard for benchmarks
 func SetupBenchmarkLogger() {
-	log.Logger = zerolog.New(io.Discard).With().Timestamp().Logger()
+	log.Logger = zerolog.New(io.Discard).With().Timestamp().Logger().Hook(zerologctx.NewHook())
+	appLogger = log.With().Str("type", "application").Logger()
 }
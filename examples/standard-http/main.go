@@ -7,61 +7,9 @@ import (
 	"net/http"
 
 	"github.com/hiiamtin/goctxid"
+	goctxid_nethttp "github.com/hiiamtin/goctxid/adapters/nethttp"
 )
 
-// Middleware for standard net/http
-func correlationIDMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// 1. Extract correlation ID from request header
-		correlationID := r.Header.Get(goctxid.DefaultHeaderKey)
-
-		// 2. If not found, generate a new one
-		if correlationID == "" {
-			correlationID = goctxid.DefaultGenerator()
-		}
-
-		// 3. Set response header
-		w.Header().Set(goctxid.DefaultHeaderKey, correlationID)
-
-		// 4. Create new context with correlation ID
-		ctx := goctxid.NewContext(r.Context(), correlationID)
-
-		// 5. Create new request with updated context
-		r = r.WithContext(ctx)
-
-		// 6. Continue to next handler
-		next.ServeHTTP(w, r)
-	})
-}
-
-// Custom middleware with configuration (example - not used in this demo)
-// nolint:unused
-func correlationIDMiddlewareWithConfig(config goctxid.Config) func(http.Handler) http.Handler {
-	// Set defaults
-	if config.HeaderKey == "" {
-		config.HeaderKey = goctxid.DefaultHeaderKey
-	}
-	if config.Generator == nil {
-		config.Generator = goctxid.DefaultGenerator
-	}
-
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			correlationID := r.Header.Get(config.HeaderKey)
-
-			if correlationID == "" {
-				correlationID = config.Generator()
-			}
-
-			w.Header().Set(config.HeaderKey, correlationID)
-			ctx := goctxid.NewContext(r.Context(), correlationID)
-			r = r.WithContext(ctx)
-
-			next.ServeHTTP(w, r)
-		})
-	}
-}
-
 // Example handler
 func helloHandler(w http.ResponseWriter, r *http.Request) {
 	correlationID, exists := goctxid.FromContext(r.Context())
@@ -118,7 +66,12 @@ func main() {
 	mux.HandleFunc("/user", userHandler)
 
 	// Wrap with correlation ID middleware
-	handler := correlationIDMiddleware(mux)
+	// Works the same way with chi, gorilla/mux, httprouter, etc.
+	handler := goctxid_nethttp.New(goctxid_nethttp.Config{
+		Next: func(r *http.Request) bool {
+			return r.URL.Path == "/health"
+		},
+	})(mux)
 
 	log.Println("Server starting on :3000")
 	log.Println("\nTry these examples:")
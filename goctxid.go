@@ -6,7 +6,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/binary"
-	"fmt"
+	"net/netip"
 	"sync"
 	"sync/atomic"
 
@@ -28,10 +28,83 @@ type Config struct {
 	// HeaderKey is the HTTP header key used to store the correlation ID
 	HeaderKey string
 
+	// InboundHeaderKeys is an ordered list of request headers accepted as the
+	// correlation ID, e.g. {"X-Correlation-ID", "X-Request-ID", "Request-Id"}.
+	// The first non-empty one is used; the response always uses HeaderKey.
+	// HeaderKey is only read from the request if it is in the list.
+	// (Default: nil, only HeaderKey is read)
+	InboundHeaderKeys []string
+
 	// Generator is the function used to generate a new correlation ID
 	// Must be thread-safe as it will be called concurrently by multiple requests
 	// (Default: UUID v4)
 	Generator func() string
+
+	// RequestGenerator generates a new correlation ID from the request, e.g. to
+	// prefix IDs with the region, tenant or route, or to reuse a load balancer ID
+	// from a non-standard header. Takes precedence over Generator when set.
+	// Must be thread-safe as it will be called concurrently by multiple requests
+	// (Default: nil, Generator is used)
+	RequestGenerator func(ctx context.Context, r RequestInfo) string
+
+	// RequestIDGenerator enables a two-level model: the correlation ID is still
+	// taken from upstream (e.g. a trusted gateway), and a new per-service request
+	// ID is generated for every request so log lines can be told apart per hop.
+	// The request ID is read with RequestIDFromContext.
+	// Must be thread-safe as it will be called concurrently by multiple requests
+	// (Default: nil, no request ID)
+	RequestIDGenerator func() string
+
+	// RequestIDHeaderKey is the response header the request ID is sent back in
+	// (Default: "", the request ID is not sent back)
+	RequestIDHeaderKey string
+
+	// TrustedProxies restricts which peers may supply their own correlation ID,
+	// e.g. {netip.MustParsePrefix("10.0.0.0/8")} for internal load balancers.
+	// The direct peer IP is used, not X-Forwarded-For. Inbound IDs from other
+	// peers are ignored and a new ID is generated.
+	// (Default: nil, with TrustInbound also nil every peer is trusted)
+	TrustedProxies []netip.Prefix
+
+	// TrustInbound reports whether inbound correlation IDs of the request can be
+	// honored. A request is trusted when it matches TrustedProxies or TrustInbound.
+	// Must be thread-safe as it will be called concurrently by multiple requests
+	// (Default: nil)
+	TrustInbound func(r RequestInfo) bool
+
+	// Signer signs the correlation IDs this service mints and only honors
+	// inbound IDs carrying a valid tag; unsigned or forged IDs are replaced by
+	// a new signed ID. Share the keys with every service that should trust
+	// the IDs. Validator, if set, sees the verified ID without its tag, so
+	// ValidateUUID and ValidateULID keep working. UseTraceParent is ignored,
+	// since a client could otherwise pick the ID through the traceparent and
+	// have it signed.
+	// (Default: nil, IDs are not signed)
+	Signer *Signer
+
+	// Validator reports whether an inbound correlation ID can be trusted.
+	// Use it to reject oversized or malformed values before they reach your logs.
+	// Must be thread-safe as it will be called concurrently by multiple requests
+	// (Default: nil, any non-empty inbound ID is accepted)
+	Validator func(id string) bool
+
+	// OnInvalid decides what happens when Validator rejects an inbound ID
+	// (Default: InvalidRegenerate)
+	OnInvalid InvalidPolicy
+
+	// UseTraceParent derives the correlation ID from the trace-id of a W3C
+	// traceparent header when the request has no usable HeaderKey header,
+	// so logs can be joined with traces. Ignored when Signer is set.
+	// (Default: false)
+	UseTraceParent bool
+
+	// EmitTraceParent sends a W3C traceparent header on responses and stores it
+	// in the context for outbound calls (see Transport and TraceParentFromContext).
+	// The trace of an inbound traceparent from a trusted peer is continued;
+	// otherwise a new trace is started with its trace-id derived from the
+	// correlation ID when possible
+	// (Default: false)
+	EmitTraceParent bool
 }
 
 // DefaultGenerator is the default UUID v4 generator
@@ -79,6 +152,7 @@ func initFastGenerator() {
 //   - IDs are used only for internal tracing (not exposed to clients)
 //
 // For most applications, use DefaultGenerator (UUID v4) instead.
+// FastOpaqueGenerator is as fast and does not expose the counter.
 //
 // Example usage:
 //
@@ -88,25 +162,123 @@ func initFastGenerator() {
 //	    },
 //	}))
 func FastGenerator() string {
+	id := nextFastID()
+	var s [36]byte
+	encodeUUIDLike(&s, &id)
+	return string(s[:])
+}
+
+// AppendFastID appends a FastGenerator ID to dst and returns the extended buffer.
+// It does not allocate when dst has enough capacity (36 bytes), which lets
+// fasthttp-based code write the ID without an intermediate string.
+//
+// The same privacy warning as FastGenerator applies.
+//
+// Example usage (fibernative):
+//
+//	buf := goctxid.AppendFastID(make([]byte, 0, 36))
+//	c.Response().Header.SetBytesV(goctxid.DefaultHeaderKey, buf)
+func AppendFastID(dst []byte) []byte {
+	id := nextFastID()
+	var s [36]byte
+	encodeUUIDLike(&s, &id)
+	return append(dst, s[:]...)
+}
+
+// nextFastID returns the seed with the next counter value in the first 8 bytes
+func nextFastID() [16]byte {
 	fastGenOnce.Do(initFastGenerator)
 
 	// Atomically increment counter
 	x := atomic.AddUint64(&fastGenCounter, 1)
 
 	// Create a copy of the seed
-	var id [24]byte
-	copy(id[:], fastGenSeed[:])
+	var id [16]byte
+	copy(id[:], fastGenSeed[:16])
 
 	// Embed counter in first 8 bytes
 	binary.LittleEndian.PutUint64(id[:8], x)
+	return id
+}
+
+var (
+	// fastOpaqueCounter is the atomic counter for the opaque fast generator
+	fastOpaqueCounter uint64
+	// fastOpaqueKeys are the Feistel round keys, derived from fastGenSeed
+	fastOpaqueKeys [4]uint32
+	// fastOpaqueOnce ensures the round keys are derived only once
+	fastOpaqueOnce sync.Once
+)
+
+// initFastOpaqueGenerator derives the Feistel round keys from the unused
+// last 8 bytes of the fast generator seed
+func initFastOpaqueGenerator() {
+	fastGenOnce.Do(initFastGenerator)
+	k := binary.LittleEndian.Uint64(fastGenSeed[16:24])
+	for i := range fastOpaqueKeys {
+		// splitmix64 step, so every round key is different
+		k += 0x9E3779B97F4A7C15
+		z := (k ^ k>>30) * 0xBF58476D1CE4E5B9
+		z = (z ^ z>>27) * 0x94D049BB133111EB
+		fastOpaqueKeys[i] = uint32(z ^ z>>31)
+	}
+}
+
+// permuteCounter maps the counter to a unique pseudo-random value with a
+// 4-round Feistel network, which is a permutation of the 64-bit values
+func permuteCounter(x uint64) uint64 {
+	l, r := uint32(x>>32), uint32(x)
+	for _, k := range fastOpaqueKeys {
+		f := uint32((uint64(r^k) * 0x9E3779B97F4A7C15) >> 32)
+		l, r = r, l^f
+	}
+	return uint64(l)<<32 | uint64(r)
+}
+
+// FastOpaqueGenerator generates correlation IDs using an atomic counter,
+// like FastGenerator, but hides the counter behind a keyed permutation.
+//
+// IDs are still unique within the process (the permutation is a bijection),
+// but consecutive IDs look random, so they do not reveal request counts or
+// ordering. The permutation key is random per process.
+//
+// It is not a cryptographic construction: use DefaultGenerator when IDs must
+// be unpredictable to a determined attacker.
+//
+// Example usage:
+//
+//	app.Use(fiber.New(fiber.Config{
+//	    Config: goctxid.Config{
+//	        Generator: goctxid.FastOpaqueGenerator,
+//	    },
+//	}))
+func FastOpaqueGenerator() string {
+	fastOpaqueOnce.Do(initFastOpaqueGenerator)
+
+	x := permuteCounter(atomic.AddUint64(&fastOpaqueCounter, 1))
+
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], x)
+	copy(id[8:], fastGenSeed[8:16])
+
+	var s [36]byte
+	encodeUUIDLike(&s, &id)
+	return string(s[:])
+}
 
-	// Format as UUID-like string (8-4-4-4-12 format)
-	return fmt.Sprintf("%x-%x-%x-%x-%x",
-		id[0:4],
-		id[4:6],
-		id[6:8],
-		id[8:10],
-		id[10:16])
+// encodeUUIDLike hex-encodes 16 bytes into s in the lowercase 8-4-4-4-12 form
+func encodeUUIDLike(s *[36]byte, id *[16]byte) {
+	const hexDigits = "0123456789abcdef"
+	j := 0
+	for i, b := range id {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			s[j] = '-'
+			j++
+		}
+		s[j] = hexDigits[b>>4]
+		s[j+1] = hexDigits[b&0x0F]
+		j += 2
+	}
 }
 
 // FromContext returns the correlation ID from the context
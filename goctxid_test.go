@@ -2,6 +2,7 @@ package goctxid
 
 import (
 	"context"
+	"sync"
 	"testing"
 )
 
@@ -300,6 +301,106 @@ func TestFastGenerator(t *testing.T) {
 	})
 }
 
+func TestAppendFastID(t *testing.T) {
+	t.Run("appends UUID-like ID", func(t *testing.T) {
+		buf := AppendFastID([]byte("id="))
+		if string(buf[:3]) != "id=" {
+			t.Errorf("Prefix was overwritten: %s", buf)
+		}
+		if id := string(buf[3:]); !ValidateUUID(id) {
+			t.Errorf("AppendFastID appended invalid ID: %s", id)
+		}
+	})
+
+	t.Run("matches FastGenerator format", func(t *testing.T) {
+		id1 := FastGenerator()
+		id2 := string(AppendFastID(nil))
+		if !ValidateUUID(id1) || !ValidateUUID(id2) {
+			t.Fatalf("Expected UUID-like IDs, got %s and %s", id1, id2)
+		}
+		// Both use the same counter and seed, so only the counter part differs
+		if id1[19:] != id2[19:] {
+			t.Errorf("IDs should share the seed part: %s, %s", id1, id2)
+		}
+		if id1 == id2 {
+			t.Errorf("Duplicate ID found: %s", id1)
+		}
+	})
+
+	t.Run("does not allocate", func(t *testing.T) {
+		buf := make([]byte, 0, 36)
+		allocs := testing.AllocsPerRun(100, func() {
+			buf = AppendFastID(buf[:0])
+		})
+		if allocs != 0 {
+			t.Errorf("AppendFastID allocated %v times, want 0", allocs)
+		}
+	})
+}
+
+func TestFastOpaqueGenerator(t *testing.T) {
+	t.Run("generates UUID-like ID", func(t *testing.T) {
+		id := FastOpaqueGenerator()
+		if !ValidateUUID(id) {
+			t.Errorf("FastOpaqueGenerator returned invalid ID: %s", id)
+		}
+	})
+
+	t.Run("does not reveal the counter", func(t *testing.T) {
+		// Consecutive counters must not give consecutive (or close) prefixes
+		id1 := FastOpaqueGenerator()
+		id2 := FastOpaqueGenerator()
+		if id1[:18] == id2[:18] {
+			t.Errorf("Consecutive IDs share their counter part: %s, %s", id1, id2)
+		}
+		if id1[19:] != id2[19:] {
+			t.Errorf("Consecutive IDs should share the seed part: %s, %s", id1, id2)
+		}
+	})
+
+	t.Run("permutation is a bijection", func(t *testing.T) {
+		fastOpaqueOnce.Do(initFastOpaqueGenerator)
+		seen := make(map[uint64]bool, 100000)
+		for x := uint64(0); x < 100000; x++ {
+			y := permuteCounter(x)
+			if seen[y] {
+				t.Fatalf("permuteCounter(%d) = %d collides", x, y)
+			}
+			seen[y] = true
+		}
+	})
+
+	t.Run("is thread-safe", func(t *testing.T) {
+		const numGoroutines = 100
+		const idsPerGoroutine = 100
+
+		ids := make(chan string, numGoroutines*idsPerGoroutine)
+		var wg sync.WaitGroup
+		for i := 0; i < numGoroutines; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < idsPerGoroutine; j++ {
+					ids <- FastOpaqueGenerator()
+				}
+			}()
+		}
+		wg.Wait()
+		close(ids)
+
+		seen := make(map[string]bool)
+		for id := range ids {
+			if seen[id] {
+				t.Errorf("Duplicate ID found: %s", id)
+			}
+			seen[id] = true
+		}
+		if len(seen) != numGoroutines*idsPerGoroutine {
+			t.Errorf("Expected %d IDs, got %d", numGoroutines*idsPerGoroutine, len(seen))
+		}
+	})
+}
+
 func BenchmarkFastGenerator(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -315,6 +416,27 @@ func BenchmarkFastGeneratorParallel(b *testing.B) {
 	})
 }
 
+func BenchmarkFastGeneratorAppendID(b *testing.B) {
+	buf := make([]byte, 0, 36)
+	for i := 0; i < b.N; i++ {
+		buf = AppendFastID(buf[:0])
+	}
+}
+
+func BenchmarkFastOpaqueGenerator(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		FastOpaqueGenerator()
+	}
+}
+
+func BenchmarkFastOpaqueGeneratorParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			FastOpaqueGenerator()
+		}
+	})
+}
+
 func BenchmarkDefaultGeneratorParallel(b *testing.B) {
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
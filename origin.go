@@ -0,0 +1,80 @@
+package goctxid
+
+import "context"
+
+// Origin tells where the correlation ID of a request came from
+type Origin int
+
+const (
+	// OriginUnknown means the origin was not recorded,
+	// e.g. the ID was stored with NewContext
+	OriginUnknown Origin = iota
+
+	// OriginHeader means the ID was read from an inbound header
+	OriginHeader
+
+	// OriginGenerated means the ID was generated by Config.Generator
+	// or Config.RequestGenerator
+	OriginGenerated
+
+	// OriginTraceParent means the ID was derived from the trace-id of an
+	// inbound traceparent header (Config.UseTraceParent)
+	OriginTraceParent
+)
+
+// String returns a short lowercase name for the origin, suitable for logs and metric labels
+func (o Origin) String() string {
+	switch o {
+	case OriginHeader:
+		return "header"
+	case OriginGenerated:
+		return "generated"
+	case OriginTraceParent:
+		return "traceparent"
+	default:
+		return "unknown"
+	}
+}
+
+// IDInfo describes the correlation ID of a request and where it came from
+type IDInfo struct {
+	// ID is the correlation ID
+	ID string
+
+	// Origin tells where ID came from
+	Origin Origin
+
+	// HeaderKey is the inbound header ID was read from
+	// (only set for OriginHeader and OriginTraceParent)
+	HeaderKey string
+
+	// Invalid is true when ID failed validation and was kept (InvalidKeep)
+	Invalid bool
+}
+
+const (
+	// infoCtxKey is the key used to store the IDInfo in the context
+	infoCtxKey correlationIDKey = "goctxid_info_key"
+)
+
+// FromContextWithInfo returns the correlation ID from the context together
+// with its origin. The origin is OriginUnknown when the ID was not stored by
+// the middleware (e.g. with NewContext).
+//
+// Example usage:
+//
+//	info, ok := goctxid.FromContextWithInfo(ctx)
+//	if ok && info.Origin == goctxid.OriginGenerated {
+//	    generatedIDs.Inc()
+//	}
+func FromContextWithInfo(ctx context.Context) (IDInfo, bool) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return IDInfo{}, false
+	}
+	// The ID may have been replaced with NewContext since it was resolved
+	if info, ok := ctx.Value(infoCtxKey).(IDInfo); ok && info.ID == id {
+		return info, true
+	}
+	return IDInfo{ID: id}, true
+}
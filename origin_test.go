@@ -0,0 +1,118 @@
+package goctxid
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContextWithInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       Config
+		headers      map[string]string
+		expectedInfo IDInfo
+	}{
+		{
+			name:         "generated",
+			config:       Config{},
+			headers:      map[string]string{},
+			expectedInfo: IDInfo{ID: "generated-id", Origin: OriginGenerated},
+		},
+		{
+			name:         "from HeaderKey",
+			config:       Config{},
+			headers:      map[string]string{DefaultHeaderKey: "client-id"},
+			expectedInfo: IDInfo{ID: "client-id", Origin: OriginHeader, HeaderKey: DefaultHeaderKey},
+		},
+		{
+			name:         "from InboundHeaderKeys",
+			config:       Config{InboundHeaderKeys: []string{DefaultHeaderKey, "X-Request-ID"}},
+			headers:      map[string]string{"X-Request-ID": "request-id"},
+			expectedInfo: IDInfo{ID: "request-id", Origin: OriginHeader, HeaderKey: "X-Request-ID"},
+		},
+		{
+			name:    "from traceparent",
+			config:  Config{UseTraceParent: true},
+			headers: map[string]string{TraceParentHeader: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			expectedInfo: IDInfo{
+				ID:        "4bf92f3577b34da6a3ce929d0e0e4736",
+				Origin:    OriginTraceParent,
+				HeaderKey: TraceParentHeader,
+			},
+		},
+		{
+			name:         "invalid kept",
+			config:       Config{Validator: ValidateUUID, OnInvalid: InvalidKeep},
+			headers:      map[string]string{DefaultHeaderKey: "bad id"},
+			expectedInfo: IDInfo{ID: "bad id", Origin: OriginHeader, HeaderKey: DefaultHeaderKey, Invalid: true},
+		},
+		{
+			name:         "invalid regenerated",
+			config:       Config{Validator: ValidateUUID},
+			headers:      map[string]string{DefaultHeaderKey: "bad id"},
+			expectedInfo: IDInfo{ID: "generated-id", Origin: OriginGenerated},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.config
+			cfg.HeaderKey = DefaultHeaderKey
+			cfg.Generator = func() string { return "generated-id" }
+
+			res, err := cfg.Resolve(func(key string) string { return tt.headers[key] })
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+
+			info, ok := FromContextWithInfo(res.NewContext(context.Background()))
+			if !ok {
+				t.Fatal("FromContextWithInfo() ok = false, want true")
+			}
+			if info != tt.expectedInfo {
+				t.Errorf("FromContextWithInfo() = %+v, want %+v", info, tt.expectedInfo)
+			}
+		})
+	}
+
+	t.Run("empty context", func(t *testing.T) {
+		if info, ok := FromContextWithInfo(context.Background()); ok || info != (IDInfo{}) {
+			t.Errorf("FromContextWithInfo() = %+v, %v, want empty, false", info, ok)
+		}
+	})
+
+	t.Run("ID set with NewContext has unknown origin", func(t *testing.T) {
+		ctx := NewContext(context.Background(), "manual-id")
+		info, ok := FromContextWithInfo(ctx)
+		if !ok || info != (IDInfo{ID: "manual-id"}) {
+			t.Errorf("FromContextWithInfo() = %+v, %v, want manual-id with unknown origin", info, ok)
+		}
+	})
+
+	t.Run("ID replaced with NewContext has unknown origin", func(t *testing.T) {
+		res := Resolution{ID: "client-id", Origin: OriginHeader, HeaderKey: DefaultHeaderKey, Invalid: true}
+		ctx := NewContext(res.NewContext(context.Background()), "replaced-id")
+		info, _ := FromContextWithInfo(ctx)
+		if info != (IDInfo{ID: "replaced-id"}) {
+			t.Errorf("FromContextWithInfo() = %+v, want replaced-id with unknown origin", info)
+		}
+		if IsInvalid(ctx) {
+			t.Error("IsInvalid() = true for a replaced ID, want false")
+		}
+	})
+}
+
+func TestOriginString(t *testing.T) {
+	tests := map[Origin]string{
+		OriginUnknown:     "unknown",
+		OriginHeader:      "header",
+		OriginGenerated:   "generated",
+		OriginTraceParent: "traceparent",
+		Origin(99):        "unknown",
+	}
+	for origin, expected := range tests {
+		if got := origin.String(); got != expected {
+			t.Errorf("Origin(%d).String() = %q, want %q", int(origin), got, expected)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package goctxid
+
+import "context"
+
+const (
+	// requestIDCtxKey is the key used to store the per-service request ID in the context
+	requestIDCtxKey correlationIDKey = "goctxid_request_id_key"
+)
+
+// NewRequestIDContext creates a new context with the per-service request ID.
+// Like NewContext, it is primarily intended for adapters and custom middleware.
+func NewRequestIDContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey, id)
+}
+
+// RequestIDFromContext returns the per-service request ID from the context.
+// It is only set when Config.RequestIDGenerator is configured; the correlation
+// ID shared across services is still returned by FromContext.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDCtxKey).(string)
+	return id, ok
+}
+
+// MustRequestIDFromContext returns the per-service request ID or empty string if not found
+func MustRequestIDFromContext(ctx context.Context) string {
+	id, _ := RequestIDFromContext(ctx)
+	return id
+}
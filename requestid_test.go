@@ -0,0 +1,94 @@
+package goctxid
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestIDContext(t *testing.T) {
+	t.Run("stores and retrieves request ID", func(t *testing.T) {
+		ctx := NewRequestIDContext(context.Background(), "request-id")
+		if id, ok := RequestIDFromContext(ctx); !ok || id != "request-id" {
+			t.Errorf("RequestIDFromContext() = %v, %v, want request-id, true", id, ok)
+		}
+		if id := MustRequestIDFromContext(ctx); id != "request-id" {
+			t.Errorf("MustRequestIDFromContext() = %v, want request-id", id)
+		}
+	})
+
+	t.Run("is separate from the correlation ID", func(t *testing.T) {
+		ctx := NewContext(context.Background(), "correlation-id")
+		if _, ok := RequestIDFromContext(ctx); ok {
+			t.Error("RequestIDFromContext() ok = true for a correlation ID only")
+		}
+		ctx = NewRequestIDContext(ctx, "request-id")
+		if id := MustFromContext(ctx); id != "correlation-id" {
+			t.Errorf("MustFromContext() = %v, want correlation-id", id)
+		}
+	})
+
+	t.Run("empty context", func(t *testing.T) {
+		if id := MustRequestIDFromContext(context.Background()); id != "" {
+			t.Errorf("MustRequestIDFromContext() = %v, want empty string", id)
+		}
+	})
+}
+
+func TestResolveRequestID(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestIDGen      func() string
+		inbound           string
+		expectedID        string
+		expectedRequestID string
+	}{
+		{
+			name:              "keeps inbound ID and generates request ID",
+			requestIDGen:      func() string { return "request-id" },
+			inbound:           "upstream-id",
+			expectedID:        "upstream-id",
+			expectedRequestID: "request-id",
+		},
+		{
+			name:              "generates both IDs without inbound ID",
+			requestIDGen:      func() string { return "request-id" },
+			inbound:           "",
+			expectedID:        "generated-id",
+			expectedRequestID: "request-id",
+		},
+		{
+			name:              "no request ID by default",
+			requestIDGen:      nil,
+			inbound:           "upstream-id",
+			expectedID:        "upstream-id",
+			expectedRequestID: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				HeaderKey:          DefaultHeaderKey,
+				Generator:          func() string { return "generated-id" },
+				RequestIDGenerator: tt.requestIDGen,
+			}
+
+			res, err := cfg.Resolve(func(string) string { return tt.inbound })
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if res.ID != tt.expectedID || res.RequestID != tt.expectedRequestID {
+				t.Errorf("Resolve() = %v, %v, want %v, %v", res.ID, res.RequestID, tt.expectedID, tt.expectedRequestID)
+			}
+
+			ctx := res.NewContext(context.Background())
+			if id := MustFromContext(ctx); id != tt.expectedID {
+				t.Errorf("MustFromContext() = %v, want %v", id, tt.expectedID)
+			}
+			id, ok := RequestIDFromContext(ctx)
+			if id != tt.expectedRequestID || ok != (tt.expectedRequestID != "") {
+				t.Errorf("RequestIDFromContext() = %v, %v, want %v", id, ok, tt.expectedRequestID)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package goctxid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidSigningKey is returned by NewSigner when no key is given or a key
+// is shorter than MinSigningKeyLength
+var ErrInvalidSigningKey = errors.New("goctxid: invalid signing key")
+
+const (
+	// MinSigningKeyLength is the minimum length of an HMAC signing key in bytes
+	MinSigningKeyLength = 16
+
+	// signatureSeparator separates the ID from its tag ("<id>.<tag>")
+	signatureSeparator = '.'
+
+	// signatureTagBytes is the length of the truncated HMAC-SHA256 tag (96 bits)
+	signatureTagBytes = 12
+)
+
+// signatureTagLength is the length of the base64url encoded tag
+var signatureTagLength = base64.RawURLEncoding.EncodedLen(signatureTagBytes)
+
+// Signer appends an HMAC-SHA256 tag to correlation IDs and verifies it, so
+// services sharing the keys can tell IDs minted by their own edge from IDs
+// forged by clients. Signed IDs look like "<id>.<tag>" where tag is 16
+// base64url characters.
+//
+// The first key signs; every key verifies. To rotate keys, deploy the new key
+// as an extra verification key everywhere, then move it to the front, then
+// drop the old key. A Signer is safe for concurrent use.
+type Signer struct {
+	keys [][]byte
+}
+
+// NewSigner creates a Signer that signs with the first key and accepts tags
+// from any of the keys. Returns ErrInvalidSigningKey if no key is given or a
+// key is shorter than MinSigningKeyLength.
+//
+// Example usage:
+//
+//	signer, err := goctxid.NewSigner(currentKey, previousKey)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	app.Use(goctxid_fiber.New(goctxid_fiber.Config{
+//	    Config: goctxid.Config{Signer: signer},
+//	}))
+func NewSigner(keys ...[]byte) (*Signer, error) {
+	if len(keys) == 0 {
+		return nil, ErrInvalidSigningKey
+	}
+	s := &Signer{keys: make([][]byte, len(keys))}
+	for i, key := range keys {
+		if len(key) < MinSigningKeyLength {
+			return nil, ErrInvalidSigningKey
+		}
+		s.keys[i] = append([]byte(nil), key...)
+	}
+	return s, nil
+}
+
+// Sign returns id with a tag appended ("<id>.<tag>")
+func (s *Signer) Sign(id string) string {
+	var buf [sha256.Size]byte
+	tag := signatureTag(s.keys[0], id, buf[:0])
+
+	var b strings.Builder
+	b.Grow(len(id) + 1 + signatureTagLength)
+	b.WriteString(id)
+	b.WriteByte(signatureSeparator)
+	b.WriteString(base64.RawURLEncoding.EncodeToString(tag))
+	return b.String()
+}
+
+// Verify reports whether signed carries a valid tag from any of the keys,
+// and returns the ID without its tag
+func (s *Signer) Verify(signed string) (string, bool) {
+	i := strings.LastIndexByte(signed, signatureSeparator)
+	if i < 0 || len(signed)-i-1 != signatureTagLength {
+		return "", false
+	}
+	id := signed[:i]
+
+	var want [signatureTagBytes]byte
+	if _, err := base64.RawURLEncoding.Decode(want[:], []byte(signed[i+1:])); err != nil {
+		return "", false
+	}
+
+	var buf [sha256.Size]byte
+	for _, key := range s.keys {
+		if hmac.Equal(signatureTag(key, id, buf[:0]), want[:]) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// signatureTag returns the truncated HMAC-SHA256 of id, appended to dst
+func signatureTag(key []byte, id string, dst []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id))
+	return mac.Sum(dst)[:signatureTagBytes]
+}
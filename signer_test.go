@@ -0,0 +1,274 @@
+package goctxid
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var (
+	testKey    = []byte("0123456789abcdef0123456789abcdef")
+	testOldKey = []byte("fedcba9876543210fedcba9876543210")
+)
+
+func TestNewSigner(t *testing.T) {
+	tests := []struct {
+		name        string
+		keys        [][]byte
+		expectedErr error
+	}{
+		{name: "one key", keys: [][]byte{testKey}},
+		{name: "rotated keys", keys: [][]byte{testKey, testOldKey}},
+		{name: "no keys", keys: nil, expectedErr: ErrInvalidSigningKey},
+		{name: "short key", keys: [][]byte{testKey, []byte("short")}, expectedErr: ErrInvalidSigningKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSigner(tt.keys...)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("NewSigner() error = %v, want %v", err, tt.expectedErr)
+			}
+		})
+	}
+
+	t.Run("copies keys", func(t *testing.T) {
+		key := bytes.Clone(testKey)
+		signer, _ := NewSigner(key)
+		signed := signer.Sign("id")
+		key[0] ^= 0xFF
+		if _, ok := signer.Verify(signed); !ok {
+			t.Error("Expected signer to be unaffected by changes to the key slice")
+		}
+	})
+}
+
+func TestSigner(t *testing.T) {
+	signer, _ := NewSigner(testKey)
+	id := "550e8400-e29b-41d4-a716-446655440000"
+	signed := signer.Sign(id)
+
+	t.Run("format", func(t *testing.T) {
+		if !strings.HasPrefix(signed, id+".") {
+			t.Errorf("Sign() = %v, want prefix %v", signed, id+".")
+		}
+		if len(signed) != len(id)+1+16 {
+			t.Errorf("Sign() length = %d, want %d", len(signed), len(id)+1+16)
+		}
+		if signer.Sign(id) != signed {
+			t.Error("Sign() is not deterministic")
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		got, ok := signer.Verify(signed)
+		if !ok || got != id {
+			t.Errorf("Verify(%s) = %v, %v, want %v, true", signed, got, ok, id)
+		}
+	})
+
+	t.Run("ID containing separator", func(t *testing.T) {
+		got, ok := signer.Verify(signer.Sign("a.b"))
+		if !ok || got != "a.b" {
+			t.Errorf("Verify() = %v, %v, want a.b, true", got, ok)
+		}
+	})
+
+	t.Run("key rotation", func(t *testing.T) {
+		oldSigner, _ := NewSigner(testOldKey)
+		rotated, _ := NewSigner(testKey, testOldKey)
+
+		if _, ok := rotated.Verify(oldSigner.Sign(id)); !ok {
+			t.Error("Expected ID signed with the old key to verify after rotation")
+		}
+		if rotated.Sign(id) != signed {
+			t.Error("Expected the first key to sign")
+		}
+		if _, ok := signer.Verify(oldSigner.Sign(id)); ok {
+			t.Error("Expected ID signed with a dropped key to fail verification")
+		}
+	})
+
+	forged := []struct {
+		name   string
+		signed string
+	}{
+		{name: "unsigned", signed: id},
+		{name: "tampered ID", signed: "650e8400" + signed[8:]},
+		{name: "tampered tag", signed: signed[:len(signed)-1] + "A"},
+		{name: "truncated tag", signed: signed[:len(signed)-1]},
+		{name: "invalid tag characters", signed: id + ".!!!!!!!!!!!!!!!!"},
+		{name: "empty", signed: ""},
+	}
+	for _, tt := range forged {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			if got, ok := signer.Verify(tt.signed); ok {
+				t.Errorf("Verify(%s) = %v, true, want false", tt.signed, got)
+			}
+		})
+	}
+}
+
+func TestResolveRequestSigner(t *testing.T) {
+	signer, _ := NewSigner(testKey)
+	uuid := "550e8400-e29b-41d4-a716-446655440000"
+
+	tests := []struct {
+		name           string
+		config         Config
+		inbound        string
+		expectedID     string
+		expectedOrigin Origin
+	}{
+		{
+			name:           "honors signed inbound ID",
+			config:         Config{Signer: signer},
+			inbound:        signer.Sign("upstream-id"),
+			expectedID:     signer.Sign("upstream-id"),
+			expectedOrigin: OriginHeader,
+		},
+		{
+			name:           "regenerates unsigned inbound ID",
+			config:         Config{Signer: signer},
+			inbound:        "upstream-id",
+			expectedID:     signer.Sign(uuid),
+			expectedOrigin: OriginGenerated,
+		},
+		{
+			name:           "regenerates forged ID even with InvalidReject",
+			config:         Config{Signer: signer, Validator: MaxLength(64), OnInvalid: InvalidReject},
+			inbound:        "upstream-id.AAAAAAAAAAAAAAAA",
+			expectedID:     signer.Sign(uuid),
+			expectedOrigin: OriginGenerated,
+		},
+		{
+			name:           "signs generated ID",
+			config:         Config{Signer: signer},
+			expectedID:     signer.Sign(uuid),
+			expectedOrigin: OriginGenerated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.config
+			cfg.HeaderKey = DefaultHeaderKey
+			cfg.Generator = func() string { return uuid }
+
+			res, err := cfg.ResolveRequest(context.Background(), RequestInfo{
+				Header: func(key string) string {
+					if key == DefaultHeaderKey {
+						return tt.inbound
+					}
+					return ""
+				},
+			})
+			if err != nil {
+				t.Fatalf("ResolveRequest() error = %v", err)
+			}
+			if res.ID != tt.expectedID {
+				t.Errorf("ResolveRequest() ID = %v, want %v", res.ID, tt.expectedID)
+			}
+			if res.Origin != tt.expectedOrigin {
+				t.Errorf("ResolveRequest() Origin = %v, want %v", res.Origin, tt.expectedOrigin)
+			}
+		})
+	}
+
+	t.Run("validator sees the ID without its tag", func(t *testing.T) {
+		for _, validator := range []func(string) bool{ValidateUUID, MaxLength(len(uuid))} {
+			cfg := Config{
+				HeaderKey: DefaultHeaderKey,
+				Generator: func() string { return "generated-id" },
+				Signer:    signer,
+				Validator: validator,
+				OnInvalid: InvalidReject,
+			}
+			signed := signer.Sign(uuid)
+			res, err := cfg.ResolveRequest(context.Background(), RequestInfo{Header: func(string) string { return signed }})
+			if err != nil {
+				t.Fatalf("ResolveRequest() error = %v", err)
+			}
+			if res.ID != signed || res.Origin != OriginHeader {
+				t.Errorf("ResolveRequest() = %v (%v), want %v from header", res.ID, res.Origin, signed)
+			}
+		}
+	})
+
+	t.Run("validator rejects a signed but malformed ID", func(t *testing.T) {
+		cfg := Config{
+			HeaderKey: DefaultHeaderKey,
+			Generator: func() string { return uuid },
+			Signer:    signer,
+			Validator: ValidateUUID,
+			OnInvalid: InvalidReject,
+		}
+		signed := signer.Sign("not-a-uuid")
+		_, err := cfg.ResolveRequest(context.Background(), RequestInfo{Header: func(string) string { return signed }})
+		if !errors.Is(err, ErrInvalidID) {
+			t.Errorf("ResolveRequest() error = %v, want %v", err, ErrInvalidID)
+		}
+	})
+
+	t.Run("traceparent cannot choose the signed ID", func(t *testing.T) {
+		cfg := Config{
+			HeaderKey:      DefaultHeaderKey,
+			Generator:      func() string { return uuid },
+			Signer:         signer,
+			UseTraceParent: true,
+		}
+		res, err := cfg.ResolveRequest(context.Background(), RequestInfo{
+			Header: func(key string) string {
+				if key == TraceParentHeader {
+					return "00-deadbeefdeadbeefdeadbeefdeadbeef-00f067aa0ba902b7-01"
+				}
+				return ""
+			},
+		})
+		if err != nil {
+			t.Fatalf("ResolveRequest() error = %v", err)
+		}
+		if res.ID != signer.Sign(uuid) || res.Origin != OriginGenerated {
+			t.Errorf("ResolveRequest() = %v (%v), want %v generated", res.ID, res.Origin, signer.Sign(uuid))
+		}
+		if unsigned, _ := signer.Verify(res.ID); strings.Contains(unsigned, "deadbeef") {
+			t.Errorf("ResolveRequest() signed the inbound trace-id: %v", res.ID)
+		}
+	})
+
+	t.Run("traceparent is derived from the unsigned ID", func(t *testing.T) {
+		cfg := Config{
+			HeaderKey:       DefaultHeaderKey,
+			Generator:       func() string { return uuid },
+			Signer:          signer,
+			EmitTraceParent: true,
+		}
+		res, err := cfg.ResolveRequest(context.Background(), RequestInfo{Header: func(string) string { return "" }})
+		if err != nil {
+			t.Fatalf("ResolveRequest() error = %v", err)
+		}
+		if got := res.TraceParent.TraceIDString(); got != strings.ReplaceAll(uuid, "-", "") {
+			t.Errorf("TraceID = %v, want %v", got, strings.ReplaceAll(uuid, "-", ""))
+		}
+	})
+}
+
+func BenchmarkSignerSign(b *testing.B) {
+	signer, _ := NewSigner(testKey)
+	id := DefaultGenerator()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = signer.Sign(id)
+	}
+}
+
+func BenchmarkSignerVerify(b *testing.B) {
+	signer, _ := NewSigner(testKey, testOldKey)
+	signed := signer.Sign(DefaultGenerator())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = signer.Verify(signed)
+	}
+}
@@ -0,0 +1,93 @@
+// Package slogctx provides a log/slog handler that adds the correlation ID
+// from the context to every log record.
+//
+// Example usage:
+//
+//	logger := slog.New(slogctx.NewHandler(slog.NewJSONHandler(os.Stdout, nil)))
+//
+//	// Inside a handler (after the goctxid middleware):
+//	logger.InfoContext(ctx, "Fetching user", "user_id", id)
+//	// {"level":"INFO","msg":"Fetching user","user_id":"42","correlation_id":"..."}
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hiiamtin/goctxid"
+)
+
+// DefaultKey is the default attribute key used for the correlation ID
+const DefaultKey = "correlation_id"
+
+// Config defines the options of the handler
+type Config struct {
+	// Key is the attribute key used for the correlation ID
+	//
+	// Optional. Default: "correlation_id"
+	Key string
+}
+
+// configDefault is a helper function that merges the provided config with the default config
+func configDefault(config ...Config) Config {
+
+	var cfg Config
+
+	// If a config is provided, use it
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Check and fill in default values
+	if cfg.Key == "" {
+		cfg.Key = DefaultKey
+	}
+
+	return cfg
+}
+
+// Handler is a slog.Handler that adds the correlation ID found in the context
+// passed to the logger (e.g. logger.InfoContext(ctx, ...)) and forwards the
+// record to the wrapped handler.
+//
+// Records logged without a context, or with a context that carries no
+// correlation ID, are forwarded unchanged. Like any attribute added by a
+// handler, the ID is placed inside the groups opened with WithGroup.
+type Handler struct {
+	next slog.Handler
+	key  string
+}
+
+// NewHandler wraps next in a Handler
+func NewHandler(next slog.Handler, config ...Config) *Handler {
+	cfg := configDefault(config...)
+	return &Handler{next: next, key: cfg.Key}
+}
+
+// Enabled implements slog.Handler
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if id := goctxid.MustFromContext(ctx); id != "" {
+		r.AddAttrs(slog.String(h.key, id))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{next: h.next.WithAttrs(attrs), key: h.key}
+}
+
+// WithGroup implements slog.Handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), key: h.key}
+}
+
+// Unwrap returns the wrapped handler
+func (h *Handler) Unwrap() slog.Handler {
+	return h.next
+}
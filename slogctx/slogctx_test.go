@@ -0,0 +1,197 @@
+package slogctx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/hiiamtin/goctxid"
+)
+
+// newTestLogger returns a logger writing JSON records to buf through a Handler
+func newTestLogger(buf *bytes.Buffer, config ...Config) *slog.Logger {
+	return slog.New(NewHandler(slog.NewJSONHandler(buf, nil), config...))
+}
+
+// decode parses a single JSON log record
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Failed to decode log record %q: %v", buf.String(), err)
+	}
+	return record
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      []Config
+		ctx         context.Context
+		expectedKey string
+		expectedID  any
+	}{
+		{
+			name:        "adds ID from context",
+			ctx:         goctxid.NewContext(context.Background(), "test-id"),
+			expectedKey: DefaultKey,
+			expectedID:  "test-id",
+		},
+		{
+			name:        "uses custom key",
+			config:      []Config{{Key: "request_id"}},
+			ctx:         goctxid.NewContext(context.Background(), "test-id"),
+			expectedKey: "request_id",
+			expectedID:  "test-id",
+		},
+		{
+			name:        "empty config uses default key",
+			config:      []Config{{}},
+			ctx:         goctxid.NewContext(context.Background(), "test-id"),
+			expectedKey: DefaultKey,
+			expectedID:  "test-id",
+		},
+		{
+			name:        "skips context without ID",
+			ctx:         context.Background(),
+			expectedKey: DefaultKey,
+			expectedID:  nil,
+		},
+		{
+			name:        "skips empty ID",
+			ctx:         goctxid.NewContext(context.Background(), ""),
+			expectedKey: DefaultKey,
+			expectedID:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := newTestLogger(&buf, tt.config...)
+
+			logger.InfoContext(tt.ctx, "hello", "user_id", "42")
+
+			record := decode(t, &buf)
+			if record[tt.expectedKey] != tt.expectedID {
+				t.Errorf("%s = %v, want %v", tt.expectedKey, record[tt.expectedKey], tt.expectedID)
+			}
+			if record["user_id"] != "42" {
+				t.Errorf("user_id = %v, want 42", record["user_id"])
+			}
+		})
+	}
+}
+
+func TestHandlerWithoutContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf)
+
+	logger.Info("hello")
+
+	record := decode(t, &buf)
+	if _, ok := record[DefaultKey]; ok {
+		t.Errorf("Unexpected %s in %v", DefaultKey, record)
+	}
+}
+
+func TestHandlerEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	logger.InfoContext(ctx, "filtered")
+	if buf.Len() != 0 {
+		t.Errorf("Expected Info to be filtered, got %q", buf.String())
+	}
+
+	logger.WarnContext(ctx, "logged")
+	if record := decode(t, &buf); record[DefaultKey] != "test-id" {
+		t.Errorf("%s = %v, want test-id", DefaultKey, record[DefaultKey])
+	}
+}
+
+func TestHandlerWithAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, Config{Key: "request_id"}).With("service", "users")
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	logger.InfoContext(ctx, "hello")
+
+	record := decode(t, &buf)
+	if record["service"] != "users" {
+		t.Errorf("service = %v, want users", record["service"])
+	}
+	if record["request_id"] != "test-id" {
+		t.Errorf("request_id = %v, want test-id", record["request_id"])
+	}
+}
+
+func TestHandlerWithGroup(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, Config{Key: "request_id"}).WithGroup("http")
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	logger.InfoContext(ctx, "hello")
+
+	record := decode(t, &buf)
+	group, ok := record["http"].(map[string]any)
+	if !ok {
+		t.Fatalf("Expected http group in %v", record)
+	}
+	if group["request_id"] != "test-id" {
+		t.Errorf("http.request_id = %v, want test-id", group["request_id"])
+	}
+}
+
+func TestHandlerUnwrap(t *testing.T) {
+	next := slog.NewTextHandler(&bytes.Buffer{}, nil)
+	if h := NewHandler(next); h.Unwrap() != next {
+		t.Error("Unwrap() did not return the wrapped handler")
+	}
+}
+
+func TestHandlerConcurrent(t *testing.T) {
+	// slog.JSONHandler serializes writes to buf
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := goctxid.NewContext(context.Background(), goctxid.DefaultGenerator())
+			logger.InfoContext(ctx, "concurrent")
+		}()
+	}
+	wg.Wait()
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 50 {
+		t.Fatalf("Expected 50 records, got %d", len(lines))
+	}
+	for _, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("Failed to decode %q: %v", line, err)
+		}
+		if id, _ := record[DefaultKey].(string); !goctxid.ValidateUUID(id) {
+			t.Errorf("Expected UUID correlation ID, got %v", record[DefaultKey])
+		}
+	}
+}
+
+func BenchmarkHandler(b *testing.B) {
+	logger := slog.New(NewHandler(slog.NewJSONHandler(io.Discard, nil)))
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.InfoContext(ctx, "benchmark")
+	}
+}
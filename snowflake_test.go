@@ -0,0 +1,221 @@
+package goctxid
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testEpoch is a fixed epoch for snowflake tests
+var testEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestNewSnowflakeGenerator(t *testing.T) {
+	tests := []struct {
+		name        string
+		nodeID      int64
+		epoch       time.Time
+		format      SnowflakeFormat
+		expectedErr error
+	}{
+		{name: "valid", nodeID: 1, epoch: testEpoch},
+		{name: "maximum node ID", nodeID: MaxSnowflakeNodeID, epoch: testEpoch, format: SnowflakeBase62},
+		{name: "negative node ID", nodeID: -1, epoch: testEpoch, expectedErr: ErrInvalidNodeID},
+		{name: "node ID too large", nodeID: MaxSnowflakeNodeID + 1, epoch: testEpoch, expectedErr: ErrInvalidNodeID},
+		{name: "epoch in the future", nodeID: 1, epoch: time.Now().Add(time.Hour), expectedErr: ErrInvalidEpoch},
+		{name: "epoch too old", nodeID: 1, epoch: time.Time{}, expectedErr: ErrInvalidEpoch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSnowflakeGenerator(tt.nodeID, tt.epoch, tt.format)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("NewSnowflakeGenerator() error = %v, want %v", err, tt.expectedErr)
+			}
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		if _, err := NewSnowflakeGenerator(1, testEpoch, SnowflakeFormat(99)); err == nil {
+			t.Error("Expected error for unknown format")
+		}
+	})
+}
+
+func TestSnowflakeGenerator(t *testing.T) {
+	formats := map[string]SnowflakeFormat{"decimal": SnowflakeDecimal, "base62": SnowflakeBase62}
+	for name, format := range formats {
+		sf, err := NewSnowflakeGenerator(42, testEpoch, format)
+		if err != nil {
+			t.Fatalf("NewSnowflakeGenerator() error = %v", err)
+		}
+
+		t.Run(name+" decodes node and time", func(t *testing.T) {
+			before := time.Now().Truncate(time.Millisecond)
+			id := sf.Generate()
+			after := time.Now()
+
+			nodeID, created, err := sf.Decode(id)
+			if err != nil {
+				t.Fatalf("Decode(%s) error = %v", id, err)
+			}
+			if nodeID != 42 {
+				t.Errorf("Decode(%s) node = %d, want 42", id, nodeID)
+			}
+			if created.Before(before) || created.After(after.Add(time.Millisecond)) {
+				t.Errorf("Decode(%s) time = %v, want between %v and %v", id, created, before, after)
+			}
+		})
+
+		t.Run(name+" is thread-safe", func(t *testing.T) {
+			const numGoroutines = 100
+			const idsPerGoroutine = 100
+
+			var mu sync.Mutex
+			seen := make(map[string]bool, numGoroutines*idsPerGoroutine)
+
+			var wg sync.WaitGroup
+			for i := 0; i < numGoroutines; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					ids := make([]string, idsPerGoroutine)
+					for j := range ids {
+						ids[j] = sf.Generate()
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					for _, id := range ids {
+						if seen[id] {
+							t.Errorf("Duplicate ID found: %s", id)
+						}
+						seen[id] = true
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+
+	t.Run("base62 IDs sort as strings", func(t *testing.T) {
+		sf, _ := NewSnowflakeGenerator(1, testEpoch, SnowflakeBase62)
+		ids := make([]string, 10000)
+		for i := range ids {
+			ids[i] = sf.Generate()
+			if len(ids[i]) != 11 {
+				t.Fatalf("Expected 11 characters, got %s", ids[i])
+			}
+		}
+		if !sort.StringsAreSorted(ids) {
+			t.Error("Base62 IDs are not sorted")
+		}
+	})
+
+	t.Run("handles clock rollback", func(t *testing.T) {
+		sf, _ := NewSnowflakeGenerator(1, testEpoch, SnowflakeDecimal)
+		now := testEpoch.Add(time.Hour)
+		sf.now = func() time.Time { return now }
+
+		prev := sf.NextID()
+		now = now.Add(-time.Minute)
+		for i := 0; i < 10000; i++ {
+			id := sf.NextID()
+			if id <= prev {
+				t.Fatalf("IDs are not increasing after clock rollback: %d <= %d", id, prev)
+			}
+			prev = id
+		}
+	})
+
+	t.Run("sequence overflow moves to the next millisecond", func(t *testing.T) {
+		sf, _ := NewSnowflakeGenerator(1, testEpoch, SnowflakeDecimal)
+		now := testEpoch.Add(time.Hour)
+		sf.now = func() time.Time { return now }
+
+		var last int64
+		for i := 0; i < 4097; i++ {
+			last = sf.NextID()
+		}
+		_, created, _ := sf.Decode(strconv.FormatInt(last, 10))
+		if want := now.Add(time.Millisecond); !created.Equal(want) {
+			t.Errorf("Expected time %v after sequence overflow, got %v", want, created)
+		}
+	})
+}
+
+func TestSnowflakeGeneratorDecode(t *testing.T) {
+	decimal, _ := NewSnowflakeGenerator(1, testEpoch, SnowflakeDecimal)
+	base62, _ := NewSnowflakeGenerator(1, testEpoch, SnowflakeBase62)
+
+	// 1000 ms after the epoch, node 5, sequence 7
+	const n = 1000<<22 | 5<<12 | 7
+
+	tests := []struct {
+		name         string
+		sf           *SnowflakeGenerator
+		id           string
+		expectedNode int64
+		expectedTime time.Time
+		expectedErr  error
+	}{
+		{
+			name:         "decimal",
+			sf:           decimal,
+			id:           strconv.FormatInt(n, 10),
+			expectedNode: 5,
+			expectedTime: testEpoch.Add(time.Second),
+		},
+		{
+			name:         "base62",
+			sf:           base62,
+			id:           formatBase62(n),
+			expectedNode: 5,
+			expectedTime: testEpoch.Add(time.Second),
+		},
+		{name: "decimal not a number", sf: decimal, id: "abc", expectedErr: ErrInvalidSnowflake},
+		{name: "decimal negative", sf: decimal, id: "-1", expectedErr: ErrInvalidSnowflake},
+		{name: "base62 wrong length", sf: base62, id: "abc", expectedErr: ErrInvalidSnowflake},
+		{name: "base62 invalid character", sf: base62, id: "0000000000-", expectedErr: ErrInvalidSnowflake},
+		{name: "base62 overflow", sf: base62, id: "zzzzzzzzzzz", expectedErr: ErrInvalidSnowflake},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeID, created, err := tt.sf.Decode(tt.id)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Decode(%s) error = %v, want %v", tt.id, err, tt.expectedErr)
+			}
+			if nodeID != tt.expectedNode {
+				t.Errorf("Decode(%s) node = %d, want %d", tt.id, nodeID, tt.expectedNode)
+			}
+			if !created.Equal(tt.expectedTime) {
+				t.Errorf("Decode(%s) time = %v, want %v", tt.id, created, tt.expectedTime)
+			}
+		})
+	}
+
+	t.Run("base62 round trip of the largest ID", func(t *testing.T) {
+		const largest = 1<<63 - 1
+		if got, err := parseBase62(formatBase62(largest)); err != nil || got != largest {
+			t.Errorf("parseBase62(formatBase62(%d)) = %d, %v", int64(largest), got, err)
+		}
+	})
+}
+
+func BenchmarkSnowflakeGenerator(b *testing.B) {
+	sf, _ := NewSnowflakeGenerator(1, testEpoch, SnowflakeBase62)
+	for i := 0; i < b.N; i++ {
+		_ = sf.Generate()
+	}
+}
+
+func BenchmarkSnowflakeGeneratorParallel(b *testing.B) {
+	sf, _ := NewSnowflakeGenerator(1, testEpoch, SnowflakeBase62)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = sf.Generate()
+		}
+	})
+}
@@ -51,10 +51,19 @@ func TestMain_FiberAdapter(t *testing.T) {
 		"package fiber",
 		"DefaultHeaderKey",
 		"DefaultGenerator",
+		"BufferedGenerator",
 		"FastGenerator",
+		"FastOpaqueGenerator",
+		"AppendFastID",
+		"UUIDv7Generator",
+		"ULIDGenerator",
 		"FromContext",
 		"MustFromContext",
+		"FromContextWithInfo",
+		"RequestIDFromContext",
+		"OriginGenerated",
 		"NewContext",
+		"IsInvalid",
 		"github.com/hiiamtin/goctxid",
 	}
 
@@ -89,9 +98,18 @@ func TestMain_FibernativeAdapter(t *testing.T) {
 		"package fibernative",
 		"DefaultHeaderKey",
 		"DefaultGenerator",
+		"BufferedGenerator",
 		"FastGenerator",
+		"FastOpaqueGenerator",
+		"AppendFastID",
+		"UUIDv7Generator",
+		"ULIDGenerator",
 		"github.com/hiiamtin/goctxid",
 		"FromLocals",
+		"FromLocalsWithInfo",
+		"RequestIDFromLocals",
+		"DetachedContext",
+		"OriginGenerated",
 		"MustFromLocals",
 		"NOT re-exported",
 	}
@@ -182,9 +200,17 @@ func TestGenerateReexports_Fiber(t *testing.T) {
 		"package fiber",
 		"DefaultHeaderKey",
 		"DefaultGenerator",
+		"BufferedGenerator",
 		"FastGenerator",
+		"FastOpaqueGenerator",
+		"AppendFastID",
+		"UUIDv7Generator",
+		"ULIDGenerator",
 		"FromContext",
 		"MustFromContext",
+		"FromContextWithInfo",
+		"RequestIDFromContext",
+		"OriginGenerated",
 		"NewContext",
 		"github.com/hiiamtin/goctxid",
 	}
@@ -211,8 +237,17 @@ func TestGenerateReexports_Fibernative(t *testing.T) {
 		"package fibernative",
 		"DefaultHeaderKey",
 		"DefaultGenerator",
+		"BufferedGenerator",
 		"FastGenerator",
+		"FastOpaqueGenerator",
+		"AppendFastID",
+		"UUIDv7Generator",
+		"ULIDGenerator",
 		"FromLocals",
+		"FromLocalsWithInfo",
+		"RequestIDFromLocals",
+		"DetachedContext",
+		"OriginGenerated",
 		"NOT re-exported",
 	}
 
@@ -285,3 +320,21 @@ func TestGenerateReexports_WriteError(t *testing.T) {
 		t.Errorf("Expected error message to contain 'error executing template', got: %v", err)
 	}
 }
+
+// TestGenerateReexports_Nethttp tests the generateReexports function with nethttp adapter
+func TestGenerateReexports_Nethttp(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateReexports("nethttp", &buf)
+	if err != nil {
+		t.Fatalf("generateReexports failed: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "package nethttp") {
+		t.Errorf("Expected output to contain 'package nethttp'")
+	}
+	if !strings.Contains(output, "func FromContext") {
+		t.Errorf("Expected nethttp adapter to re-export context functions")
+	}
+}
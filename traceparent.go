@@ -0,0 +1,188 @@
+package goctxid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+)
+
+const (
+	// TraceParentHeader is the W3C Trace Context header carrying the trace-id
+	// and parent-id of a request
+	TraceParentHeader = "traceparent"
+
+	// traceParentCtxKey is the key used to store the traceparent in the context
+	traceParentCtxKey correlationIDKey = "goctxid_traceparent_key"
+
+	// traceParentLen is the length of a version 00 traceparent header
+	traceParentLen = 55
+
+	// traceFlagsSampled is the sampled flag of trace-flags
+	traceFlagsSampled = 0x01
+)
+
+// ErrInvalidTraceParent is returned by ParseTraceParent for malformed headers
+var ErrInvalidTraceParent = errors.New("goctxid: invalid traceparent")
+
+// TraceParent is a parsed W3C Trace Context traceparent header.
+// See https://www.w3.org/TR/trace-context/#traceparent-header
+type TraceParent struct {
+	// TraceID is the ID of the whole trace
+	TraceID [16]byte
+
+	// ParentID is the ID of the caller's span
+	ParentID [8]byte
+
+	// Flags are the trace-flags (bit 0 is "sampled")
+	Flags byte
+}
+
+// ParseTraceParent parses a traceparent header value following the W3C spec:
+//   - version 00 must be exactly "00-<32 hex trace-id>-<16 hex parent-id>-<2 hex flags>"
+//   - version ff is invalid
+//   - higher versions are parsed by their first four fields and may carry more
+//     fields after a dash
+//   - hex must be lowercase, and all-zero trace-id or parent-id are invalid
+func ParseTraceParent(s string) (TraceParent, error) {
+	var tp TraceParent
+
+	if len(s) < traceParentLen || s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return tp, ErrInvalidTraceParent
+	}
+
+	version, ok := decodeLowerHex(s[0:2])
+	if !ok || version[0] == 0xff {
+		return tp, ErrInvalidTraceParent
+	}
+	// Version 00 has a fixed length; future versions may append fields
+	if version[0] == 0x00 && len(s) != traceParentLen {
+		return tp, ErrInvalidTraceParent
+	}
+	if len(s) > traceParentLen && s[traceParentLen] != '-' {
+		return tp, ErrInvalidTraceParent
+	}
+
+	traceID, ok := decodeLowerHex(s[3:35])
+	if !ok {
+		return tp, ErrInvalidTraceParent
+	}
+	parentID, ok := decodeLowerHex(s[36:52])
+	if !ok {
+		return tp, ErrInvalidTraceParent
+	}
+	flags, ok := decodeLowerHex(s[53:55])
+	if !ok {
+		return tp, ErrInvalidTraceParent
+	}
+
+	copy(tp.TraceID[:], traceID)
+	copy(tp.ParentID[:], parentID)
+	tp.Flags = flags[0]
+
+	if !tp.IsValid() {
+		return TraceParent{}, ErrInvalidTraceParent
+	}
+	return tp, nil
+}
+
+// NewTraceParent creates a traceparent for a request with the given correlation ID.
+// The trace-id is derived from the ID when it is a UUID or 32 hex characters
+// (so traces and logs share the same ID), and random otherwise.
+// The parent-id is random and the trace is marked as sampled.
+func NewTraceParent(correlationID string) TraceParent {
+	var tp TraceParent
+
+	if !traceIDFromID(correlationID, &tp.TraceID) {
+		_, _ = rand.Read(tp.TraceID[:])
+	}
+	_, _ = rand.Read(tp.ParentID[:])
+	tp.Flags = traceFlagsSampled
+
+	return tp
+}
+
+// Child returns a traceparent in the same trace with a new random parent-id,
+// keeping the sampled flag. Use it when forwarding a trace to another service.
+func (tp TraceParent) Child() TraceParent {
+	child := TraceParent{TraceID: tp.TraceID, Flags: tp.Flags & traceFlagsSampled}
+	_, _ = rand.Read(child.ParentID[:])
+	return child
+}
+
+// IsValid reports whether both the trace-id and parent-id are non-zero
+func (tp TraceParent) IsValid() bool {
+	return tp.TraceID != [16]byte{} && tp.ParentID != [8]byte{}
+}
+
+// TraceIDString returns the trace-id as 32 lowercase hex characters
+func (tp TraceParent) TraceIDString() string {
+	return hex.EncodeToString(tp.TraceID[:])
+}
+
+// String returns the version 00 traceparent header value
+func (tp TraceParent) String() string {
+	var b [traceParentLen]byte
+	b[0], b[1], b[2] = '0', '0', '-'
+	hex.Encode(b[3:35], tp.TraceID[:])
+	b[35] = '-'
+	hex.Encode(b[36:52], tp.ParentID[:])
+	b[52] = '-'
+	hex.Encode(b[53:55], []byte{tp.Flags})
+	return string(b[:])
+}
+
+// NewTraceParentContext creates a new context with the traceparent
+func NewTraceParentContext(ctx context.Context, tp TraceParent) context.Context {
+	return context.WithValue(ctx, traceParentCtxKey, tp)
+}
+
+// TraceParentFromContext returns the traceparent stored in the context by the
+// middleware (see Config.EmitTraceParent)
+func TraceParentFromContext(ctx context.Context) (TraceParent, bool) {
+	tp, ok := ctx.Value(traceParentCtxKey).(TraceParent)
+	return tp, ok
+}
+
+// OutgoingTraceParent returns the traceparent to send on an outbound call made
+// with ctx: the one stored by the middleware, or a new one derived from the
+// correlation ID in ctx. Returns false when ctx carries neither.
+func OutgoingTraceParent(ctx context.Context) (TraceParent, bool) {
+	if tp, ok := TraceParentFromContext(ctx); ok {
+		return tp, true
+	}
+	if id, ok := FromContext(ctx); ok && id != "" {
+		return NewTraceParent(id), true
+	}
+	return TraceParent{}, false
+}
+
+// traceIDFromID fills traceID from a UUID or 32-hex correlation ID.
+// Returns false if the ID has another format or would give an all-zero trace-id.
+func traceIDFromID(id string, traceID *[16]byte) bool {
+	var raw string
+	switch {
+	case len(id) == 32:
+		raw = id
+	case ValidateUUID(id):
+		raw = id[0:8] + id[9:13] + id[14:18] + id[19:23] + id[24:36]
+	default:
+		return false
+	}
+
+	if _, err := hex.Decode(traceID[:], []byte(raw)); err != nil {
+		return false
+	}
+	return *traceID != [16]byte{}
+}
+
+// decodeLowerHex decodes s, which must only contain lowercase hex digits
+func decodeLowerHex(s string) ([]byte, bool) {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !('0' <= c && c <= '9') && !('a' <= c && c <= 'f') {
+			return nil, false
+		}
+	}
+	b, err := hex.DecodeString(s)
+	return b, err == nil
+}
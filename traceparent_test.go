@@ -0,0 +1,381 @@
+package goctxid
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const validTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr bool
+	}{
+		{name: "valid version 00", input: validTraceParent},
+		{name: "valid not sampled", input: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"},
+		{name: "future version with extra fields", input: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"},
+		{name: "future version without extra fields", input: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "empty", input: "", expectedErr: true},
+		{name: "too short", input: validTraceParent[:54], expectedErr: true},
+		{name: "version 00 with extra fields", input: validTraceParent + "-extra", expectedErr: true},
+		{name: "future version without dash before extra", input: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01x", expectedErr: true},
+		{name: "version ff", input: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", expectedErr: true},
+		{name: "invalid version", input: "0x-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", expectedErr: true},
+		{name: "uppercase trace-id", input: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", expectedErr: true},
+		{name: "uppercase parent-id", input: "00-4bf92f3577b34da6a3ce929d0e0e4736-00F067AA0BA902B7-01", expectedErr: true},
+		{name: "invalid flags", input: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0g", expectedErr: true},
+		{name: "wrong separator", input: "00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", expectedErr: true},
+		{name: "zero trace-id", input: "00-00000000000000000000000000000000-00f067aa0ba902b7-01", expectedErr: true},
+		{name: "zero parent-id", input: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, err := ParseTraceParent(tt.input)
+			if tt.expectedErr {
+				if !errors.Is(err, ErrInvalidTraceParent) {
+					t.Errorf("Expected ErrInvalidTraceParent, got %v", err)
+				}
+				if tp.IsValid() {
+					t.Errorf("Expected zero TraceParent on error, got %v", tp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tp.TraceIDString() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+				t.Errorf("Expected trace-id 4bf92f3577b34da6a3ce929d0e0e4736, got %s", tp.TraceIDString())
+			}
+		})
+	}
+}
+
+func TestTraceParentString(t *testing.T) {
+	tp, err := ParseTraceParent(validTraceParent)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tp.String() != validTraceParent {
+		t.Errorf("Expected %s, got %s", validTraceParent, tp.String())
+	}
+
+	// Future versions are written back as version 00
+	tp, _ = ParseTraceParent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra")
+	if tp.String() != validTraceParent {
+		t.Errorf("Expected %s, got %s", validTraceParent, tp.String())
+	}
+}
+
+func TestNewTraceParent(t *testing.T) {
+	tests := []struct {
+		name            string
+		id              string
+		expectedTraceID string
+	}{
+		{
+			name:            "derives trace-id from UUID",
+			id:              "4bf92f35-77b3-4da6-a3ce-929d0e0e4736",
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:            "derives trace-id from uppercase UUID",
+			id:              "4BF92F35-77B3-4DA6-A3CE-929D0E0E4736",
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:            "derives trace-id from 32 hex characters",
+			id:              "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{name: "random trace-id for other IDs", id: "custom-id"},
+		{name: "random trace-id for 32 non-hex characters", id: "zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz"},
+		{name: "random trace-id for zero UUID", id: "00000000-0000-0000-0000-000000000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp := NewTraceParent(tt.id)
+			if !tp.IsValid() {
+				t.Fatalf("Expected valid TraceParent, got %v", tp)
+			}
+			if tp.Flags != traceFlagsSampled {
+				t.Errorf("Expected sampled flag, got %02x", tp.Flags)
+			}
+			if tt.expectedTraceID != "" && tp.TraceIDString() != tt.expectedTraceID {
+				t.Errorf("Expected trace-id %s, got %s", tt.expectedTraceID, tp.TraceIDString())
+			}
+			if _, err := ParseTraceParent(tp.String()); err != nil {
+				t.Errorf("Expected String() to be parseable, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTraceParentChild(t *testing.T) {
+	parent, _ := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-ff")
+	child := parent.Child()
+
+	if child.TraceID != parent.TraceID {
+		t.Errorf("Expected same trace-id, got %s", child.TraceIDString())
+	}
+	if child.ParentID == parent.ParentID {
+		t.Error("Expected a new parent-id")
+	}
+	if child.Flags != traceFlagsSampled {
+		t.Errorf("Expected only the sampled flag, got %02x", child.Flags)
+	}
+}
+
+func TestTraceParentContext(t *testing.T) {
+	tp := NewTraceParent("custom-id")
+
+	if _, ok := TraceParentFromContext(context.Background()); ok {
+		t.Error("Expected no traceparent in empty context")
+	}
+
+	ctx := NewTraceParentContext(context.Background(), tp)
+	got, ok := TraceParentFromContext(ctx)
+	if !ok || got != tp {
+		t.Errorf("Expected %v, got %v (ok=%v)", tp, got, ok)
+	}
+}
+
+func TestOutgoingTraceParent(t *testing.T) {
+	stored := NewTraceParent("custom-id")
+
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		expectedOK      bool
+		expectedTraceID string
+	}{
+		{
+			name:            "uses stored traceparent",
+			ctx:             NewTraceParentContext(NewContext(context.Background(), "other-id"), stored),
+			expectedOK:      true,
+			expectedTraceID: stored.TraceIDString(),
+		},
+		{
+			name:            "derives from correlation ID",
+			ctx:             NewContext(context.Background(), "4bf92f35-77b3-4da6-a3ce-929d0e0e4736"),
+			expectedOK:      true,
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:       "empty correlation ID",
+			ctx:        NewContext(context.Background(), ""),
+			expectedOK: false,
+		},
+		{
+			name:       "empty context",
+			ctx:        context.Background(),
+			expectedOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp, ok := OutgoingTraceParent(tt.ctx)
+			if ok != tt.expectedOK {
+				t.Fatalf("Expected ok=%v, got %v", tt.expectedOK, ok)
+			}
+			if ok && tp.TraceIDString() != tt.expectedTraceID {
+				t.Errorf("Expected trace-id %s, got %s", tt.expectedTraceID, tp.TraceIDString())
+			}
+		})
+	}
+}
+
+func TestResolveTraceParent(t *testing.T) {
+	generator := func() string { return "generated-id" }
+	rejectAll := func(string) bool { return false }
+
+	tests := []struct {
+		name               string
+		config             Config
+		inboundID          string
+		inboundTraceParent string
+		expectedID         string
+		expectedTraceID    string
+		expectedEmit       bool
+	}{
+		{
+			name:               "ignores traceparent by default",
+			config:             Config{},
+			inboundTraceParent: validTraceParent,
+			expectedID:         "generated-id",
+		},
+		{
+			name:               "derives ID from traceparent",
+			config:             Config{UseTraceParent: true},
+			inboundTraceParent: validTraceParent,
+			expectedID:         "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:               "prefers the correlation ID header",
+			config:             Config{UseTraceParent: true},
+			inboundID:          "client-id",
+			inboundTraceParent: validTraceParent,
+			expectedID:         "client-id",
+		},
+		{
+			name:               "derives ID from traceparent when inbound ID is invalid",
+			config:             Config{UseTraceParent: true, Validator: rejectAll},
+			inboundID:          "client-id",
+			inboundTraceParent: validTraceParent,
+			expectedID:         "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:               "generates ID when traceparent is malformed",
+			config:             Config{UseTraceParent: true},
+			inboundTraceParent: "garbage",
+			expectedID:         "generated-id",
+		},
+		{
+			name:               "emits child of inbound traceparent",
+			config:             Config{EmitTraceParent: true},
+			inboundID:          "client-id",
+			inboundTraceParent: validTraceParent,
+			expectedID:         "client-id",
+			expectedTraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectedEmit:       true,
+		},
+		{
+			name:            "emits new traceparent derived from ID",
+			config:          Config{EmitTraceParent: true},
+			inboundID:       "4bf92f35-77b3-4da6-a3ce-929d0e0e4736",
+			expectedID:      "4bf92f35-77b3-4da6-a3ce-929d0e0e4736",
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectedEmit:    true,
+		},
+		{
+			name:         "emits new traceparent for other IDs",
+			config:       Config{EmitTraceParent: true},
+			expectedID:   "generated-id",
+			expectedEmit: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.config
+			cfg.HeaderKey = DefaultHeaderKey
+			cfg.Generator = generator
+
+			header := http.Header{}
+			header.Set(DefaultHeaderKey, tt.inboundID)
+			header.Set(TraceParentHeader, tt.inboundTraceParent)
+
+			res, err := cfg.Resolve(header.Get)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if res.ID != tt.expectedID {
+				t.Errorf("Expected ID %s, got %s", tt.expectedID, res.ID)
+			}
+			if res.TraceParent.IsValid() != tt.expectedEmit {
+				t.Fatalf("Expected TraceParent valid=%v, got %v", tt.expectedEmit, res.TraceParent)
+			}
+			if tt.expectedTraceID != "" && res.TraceParent.TraceIDString() != tt.expectedTraceID {
+				t.Errorf("Expected trace-id %s, got %s", tt.expectedTraceID, res.TraceParent.TraceIDString())
+			}
+
+			ctx := res.NewContext(context.Background())
+			if _, ok := TraceParentFromContext(ctx); ok != tt.expectedEmit {
+				t.Errorf("Expected traceparent in context=%v, got %v", tt.expectedEmit, ok)
+			}
+		})
+	}
+}
+
+func TestTransportTraceParent(t *testing.T) {
+	stored := NewTraceParent("custom-id")
+
+	tests := []struct {
+		name                string
+		transport           Transport
+		ctx                 context.Context
+		existingTraceParent string
+		expectedTraceParent string
+		expectedTraceID     string
+	}{
+		{
+			name:      "does not send traceparent by default",
+			transport: Transport{},
+			ctx:       NewTraceParentContext(NewContext(context.Background(), "ctx-id"), stored),
+		},
+		{
+			name:                "sends stored traceparent",
+			transport:           Transport{TraceParent: true},
+			ctx:                 NewTraceParentContext(NewContext(context.Background(), "ctx-id"), stored),
+			expectedTraceParent: stored.String(),
+		},
+		{
+			name:            "derives traceparent from correlation ID",
+			transport:       Transport{TraceParent: true},
+			ctx:             NewContext(context.Background(), "4bf92f35-77b3-4da6-a3ce-929d0e0e4736"),
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:                "keeps existing traceparent",
+			transport:           Transport{TraceParent: true},
+			ctx:                 NewTraceParentContext(NewContext(context.Background(), "ctx-id"), stored),
+			existingTraceParent: validTraceParent,
+			expectedTraceParent: validTraceParent,
+		},
+		{
+			name:                "sends traceparent when correlation ID is preserved",
+			transport:           Transport{TraceParent: true, PreserveExisting: true},
+			ctx:                 NewTraceParentContext(context.Background(), stored),
+			expectedTraceParent: stored.String(),
+		},
+		{
+			name:      "sends nothing without ID or traceparent",
+			transport: Transport{TraceParent: true},
+			ctx:       context.Background(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			tr := tt.transport
+			tr.Base = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				got = req.Header.Get(TraceParentHeader)
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			})
+
+			req, _ := http.NewRequestWithContext(tt.ctx, http.MethodGet, "http://example.com", nil)
+			if tt.existingTraceParent != "" {
+				req.Header.Set(TraceParentHeader, tt.existingTraceParent)
+			}
+
+			if _, err := tr.RoundTrip(req); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			switch {
+			case tt.expectedTraceID != "":
+				tp, err := ParseTraceParent(got)
+				if err != nil {
+					t.Fatalf("Expected valid traceparent, got %q", got)
+				}
+				if tp.TraceIDString() != tt.expectedTraceID {
+					t.Errorf("Expected trace-id %s, got %s", tt.expectedTraceID, tp.TraceIDString())
+				}
+			case got != tt.expectedTraceParent:
+				t.Errorf("Expected traceparent %q, got %q", tt.expectedTraceParent, got)
+			}
+
+			// The original request must not be modified
+			if req.Header.Get(TraceParentHeader) != tt.existingTraceParent {
+				t.Error("Original request was modified")
+			}
+		})
+	}
+}
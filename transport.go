@@ -0,0 +1,88 @@
+package goctxid
+
+import (
+	"net/http"
+)
+
+// Transport is an http.RoundTripper that propagates the correlation ID stored in
+// the request context (see FromContext) to outgoing requests as a header.
+// It wraps any http.RoundTripper and is safe for concurrent use.
+//
+// Example usage:
+//
+//	client := &http.Client{
+//	    Transport: &goctxid.Transport{},
+//	}
+//
+//	// Inside a handler: the downstream service receives the same ID
+//	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+//	resp, err := client.Do(req)
+type Transport struct {
+	// Base is the RoundTripper used to send the request
+	// (Default: http.DefaultTransport)
+	Base http.RoundTripper
+
+	// HeaderKey is the header used to send the correlation ID
+	// (Default: "X-Correlation-ID")
+	HeaderKey string
+
+	// PreserveExisting keeps a correlation ID header that was already set on the
+	// outgoing request instead of overwriting it with the one from the context
+	// (Default: false)
+	PreserveExisting bool
+
+	// Generator is used to generate a correlation ID when the request context
+	// does not carry one. Must be thread-safe.
+	// (Default: nil, requests without an ID in context are sent unchanged)
+	Generator func() string
+
+	// TraceParent also sends a W3C traceparent header (see OutgoingTraceParent),
+	// unless the request already has one, e.g. set by a tracing library
+	// (Default: false)
+	TraceParent bool
+}
+
+// RoundTrip implements http.RoundTripper.
+// The original request is never modified; a clone is sent when a header is added.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	headerKey := t.HeaderKey
+	if headerKey == "" {
+		headerKey = DefaultHeaderKey
+	}
+
+	// Keep the caller's header if asked to
+	var id string
+	if !t.PreserveExisting || req.Header.Get(headerKey) == "" {
+		// Take the ID from context, or generate one if configured
+		var ok bool
+		id, ok = FromContext(req.Context())
+		if (!ok || id == "") && t.Generator != nil {
+			id = t.Generator()
+		}
+	}
+
+	var traceParent string
+	if t.TraceParent && req.Header.Get(TraceParentHeader) == "" {
+		if tp, ok := OutgoingTraceParent(req.Context()); ok {
+			traceParent = tp.String()
+		}
+	}
+
+	if id == "" && traceParent == "" {
+		return base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the request, so set the headers on a clone
+	out := req.Clone(req.Context())
+	if id != "" {
+		out.Header.Set(headerKey, id)
+	}
+	if traceParent != "" {
+		out.Header.Set(TraceParentHeader, traceParent)
+	}
+	return base.RoundTrip(out)
+}
@@ -0,0 +1,181 @@
+package goctxid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTransport(t *testing.T) {
+	tests := []struct {
+		name           string
+		transport      Transport
+		ctx            context.Context
+		existingHeader string
+		expectedHeader string
+		checkKey       string
+	}{
+		{
+			name:           "sets header from context",
+			transport:      Transport{},
+			ctx:            NewContext(context.Background(), "ctx-id"),
+			expectedHeader: "ctx-id",
+			checkKey:       DefaultHeaderKey,
+		},
+		{
+			name:           "uses custom header key",
+			transport:      Transport{HeaderKey: "X-Request-ID"},
+			ctx:            NewContext(context.Background(), "ctx-id"),
+			expectedHeader: "ctx-id",
+			checkKey:       "X-Request-ID",
+		},
+		{
+			name:           "overwrites existing header by default",
+			transport:      Transport{},
+			ctx:            NewContext(context.Background(), "ctx-id"),
+			existingHeader: "caller-id",
+			expectedHeader: "ctx-id",
+			checkKey:       DefaultHeaderKey,
+		},
+		{
+			name:           "preserves existing header when configured",
+			transport:      Transport{PreserveExisting: true},
+			ctx:            NewContext(context.Background(), "ctx-id"),
+			existingHeader: "caller-id",
+			expectedHeader: "caller-id",
+			checkKey:       DefaultHeaderKey,
+		},
+		{
+			name:           "sets header when preserving and no header exists",
+			transport:      Transport{PreserveExisting: true},
+			ctx:            NewContext(context.Background(), "ctx-id"),
+			expectedHeader: "ctx-id",
+			checkKey:       DefaultHeaderKey,
+		},
+		{
+			name:           "leaves request unchanged without ID in context",
+			transport:      Transport{},
+			ctx:            context.Background(),
+			expectedHeader: "",
+			checkKey:       DefaultHeaderKey,
+		},
+		{
+			name:           "generates ID when context has none",
+			transport:      Transport{Generator: func() string { return "generated-id" }},
+			ctx:            context.Background(),
+			expectedHeader: "generated-id",
+			checkKey:       DefaultHeaderKey,
+		},
+		{
+			name:           "generates ID when context has empty ID",
+			transport:      Transport{Generator: func() string { return "generated-id" }},
+			ctx:            NewContext(context.Background(), ""),
+			expectedHeader: "generated-id",
+			checkKey:       DefaultHeaderKey,
+		},
+		{
+			name:           "prefers context ID over generator",
+			transport:      Transport{Generator: func() string { return "generated-id" }},
+			ctx:            NewContext(context.Background(), "ctx-id"),
+			expectedHeader: "ctx-id",
+			checkKey:       DefaultHeaderKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sentHeader string
+			tr := tt.transport
+			tr.Base = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				sentHeader = req.Header.Get(tt.checkKey)
+				return &http.Response{StatusCode: http.StatusOK, Request: req}, nil
+			})
+
+			req, _ := http.NewRequestWithContext(tt.ctx, "GET", "http://example.com", nil)
+			if tt.existingHeader != "" {
+				req.Header.Set(tt.checkKey, tt.existingHeader)
+			}
+
+			if _, err := tr.RoundTrip(req); err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+
+			if sentHeader != tt.expectedHeader {
+				t.Errorf("Sent header = %q, want %q", sentHeader, tt.expectedHeader)
+			}
+			if got := req.Header.Get(tt.checkKey); got != tt.existingHeader {
+				t.Errorf("Original request was modified: header = %q, want %q", got, tt.existingHeader)
+			}
+		})
+	}
+}
+
+func TestTransportDefaultBase(t *testing.T) {
+	// Downstream service that echoes the received correlation ID
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(DefaultHeaderKey, r.Header.Get(DefaultHeaderKey))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{}}
+
+	ctx := NewContext(context.Background(), "end-to-end-id")
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if got := resp.Header.Get(DefaultHeaderKey); got != "end-to-end-id" {
+		t.Errorf("Downstream received %q, want %q", got, "end-to-end-id")
+	}
+}
+
+func TestTransportConcurrent(t *testing.T) {
+	tr := &Transport{
+		Base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get(DefaultHeaderKey) != MustFromContext(req.Context()) {
+				t.Errorf("Header %q does not match context ID %q",
+					req.Header.Get(DefaultHeaderKey), MustFromContext(req.Context()))
+			}
+			return &http.Response{StatusCode: http.StatusOK, Request: req}, nil
+		}),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := NewContext(context.Background(), DefaultGenerator())
+			req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com", nil)
+			_, _ = tr.RoundTrip(req)
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkTransport(b *testing.B) {
+	tr := &Transport{
+		Base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, nil
+		}),
+	}
+	ctx := NewContext(context.Background(), "benchmark-id")
+	req, _ := http.NewRequestWithContext(ctx, "GET", "http://example.com", nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = tr.RoundTrip(req)
+	}
+}
@@ -0,0 +1,32 @@
+package goctxid
+
+import "net/netip"
+
+// trusts reports whether inbound correlation IDs of the request can be honored
+// according to TrustedProxies and TrustInbound
+func (c Config) trusts(r RequestInfo) bool {
+	if len(c.TrustedProxies) == 0 && c.TrustInbound == nil {
+		return true
+	}
+	if ip := r.RemoteIP.Unmap(); ip.IsValid() {
+		for _, prefix := range c.TrustedProxies {
+			if prefix.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return c.TrustInbound != nil && c.TrustInbound(r)
+}
+
+// ParseRemoteIP returns the IP address of a remote address in the "IP:port"
+// or "IP" form (e.g. http.Request.RemoteAddr), or the zero netip.Addr if it
+// cannot be parsed. Intended for adapters filling in RequestInfo.RemoteIP.
+func ParseRemoteIP(remoteAddr string) netip.Addr {
+	if addrPort, err := netip.ParseAddrPort(remoteAddr); err == nil {
+		return addrPort.Addr().Unmap()
+	}
+	if addr, err := netip.ParseAddr(remoteAddr); err == nil {
+		return addr.Unmap()
+	}
+	return netip.Addr{}
+}
@@ -0,0 +1,136 @@
+package goctxid
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+)
+
+func TestTrustPolicy(t *testing.T) {
+	lb := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("2001:db8::/32")}
+	byHeader := func(r RequestInfo) bool { return r.Header("X-Internal") == "yes" }
+
+	tests := []struct {
+		name       string
+		config     Config
+		remoteIP   string
+		headers    map[string]string
+		expectedID string
+	}{
+		{
+			name:       "trusts everyone without policy",
+			config:     Config{},
+			remoteIP:   "203.0.113.7",
+			expectedID: "client-id",
+		},
+		{
+			name:       "honors ID from trusted proxy",
+			config:     Config{TrustedProxies: lb},
+			remoteIP:   "10.1.2.3",
+			expectedID: "client-id",
+		},
+		{
+			name:       "honors ID from trusted IPv6 proxy",
+			config:     Config{TrustedProxies: lb},
+			remoteIP:   "2001:db8::1",
+			expectedID: "client-id",
+		},
+		{
+			name:       "honors ID from IPv4-mapped trusted proxy",
+			config:     Config{TrustedProxies: lb},
+			remoteIP:   "::ffff:10.1.2.3",
+			expectedID: "client-id",
+		},
+		{
+			name:       "regenerates ID from untrusted peer",
+			config:     Config{TrustedProxies: lb},
+			remoteIP:   "203.0.113.7",
+			expectedID: "generated-id",
+		},
+		{
+			name:       "regenerates ID when peer is unknown",
+			config:     Config{TrustedProxies: lb},
+			remoteIP:   "",
+			expectedID: "generated-id",
+		},
+		{
+			name:       "honors ID accepted by predicate",
+			config:     Config{TrustInbound: byHeader},
+			remoteIP:   "203.0.113.7",
+			headers:    map[string]string{"X-Internal": "yes"},
+			expectedID: "client-id",
+		},
+		{
+			name:       "regenerates ID rejected by predicate",
+			config:     Config{TrustInbound: byHeader},
+			remoteIP:   "203.0.113.7",
+			expectedID: "generated-id",
+		},
+		{
+			name:       "trusts when either proxy or predicate accepts",
+			config:     Config{TrustedProxies: lb, TrustInbound: byHeader},
+			remoteIP:   "203.0.113.7",
+			headers:    map[string]string{"X-Internal": "yes"},
+			expectedID: "client-id",
+		},
+		{
+			name:       "ignores traceparent from untrusted peer",
+			config:     Config{TrustedProxies: lb, UseTraceParent: true},
+			remoteIP:   "203.0.113.7",
+			headers:    map[string]string{TraceParentHeader: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			expectedID: "generated-id",
+		},
+		{
+			name:       "does not validate ID from untrusted peer",
+			config:     Config{TrustedProxies: lb, Validator: ValidateUUID, OnInvalid: InvalidReject},
+			remoteIP:   "203.0.113.7",
+			expectedID: "generated-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.config
+			cfg.HeaderKey = DefaultHeaderKey
+			cfg.Generator = func() string { return "generated-id" }
+
+			headers := map[string]string{DefaultHeaderKey: "client-id"}
+			for key, value := range tt.headers {
+				headers[key] = value
+			}
+			r := RequestInfo{Header: func(key string) string { return headers[key] }}
+			if tt.remoteIP != "" {
+				r.RemoteIP = netip.MustParseAddr(tt.remoteIP)
+			}
+
+			res, err := cfg.ResolveRequest(context.Background(), r)
+			if err != nil {
+				t.Fatalf("ResolveRequest() error = %v", err)
+			}
+			if res.ID != tt.expectedID {
+				t.Errorf("ResolveRequest() ID = %v, want %v", res.ID, tt.expectedID)
+			}
+		})
+	}
+}
+
+func TestParseRemoteIP(t *testing.T) {
+	tests := []struct {
+		remoteAddr string
+		expected   string
+	}{
+		{"192.0.2.1:1234", "192.0.2.1"},
+		{"192.0.2.1", "192.0.2.1"},
+		{"[2001:db8::1]:443", "2001:db8::1"},
+		{"2001:db8::1", "2001:db8::1"},
+		{"[::ffff:192.0.2.1]:80", "192.0.2.1"},
+		{"bufconn", "invalid IP"},
+		{"", "invalid IP"},
+	}
+
+	for _, tt := range tests {
+		if got := ParseRemoteIP(tt.remoteAddr).String(); got != tt.expected {
+			t.Errorf("ParseRemoteIP(%q) = %v, want %v", tt.remoteAddr, got, tt.expected)
+		}
+	}
+}
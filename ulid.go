@@ -0,0 +1,147 @@
+package goctxid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInvalidULID is returned by ParseULID when the ID is not a valid ULID
+var ErrInvalidULID = errors.New("goctxid: invalid ULID")
+
+// ULID is a 128-bit Universally Unique Lexicographically Sortable Identifier.
+// The first 48 bits are the Unix time in milliseconds.
+type ULID [16]byte
+
+// crockfordAlphabet is the Crockford base32 alphabet used to encode ULIDs
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidState holds the upper 64 bits of the last generated ULID:
+// the 48-bit millisecond timestamp followed by a 16-bit sequence.
+// It is updated with compare-and-swap so generation never takes a lock.
+var ulidState atomic.Uint64
+
+// ULIDGenerator generates ULID correlation IDs (https://github.com/ulid/spec).
+//
+// IDs are 26 characters of Crockford base32 and sort lexicographically by
+// creation time, which makes them shorter than UUIDs and easy to scan in log UIs.
+// IDs are strictly increasing within a process, including within the same
+// millisecond and across goroutines. The generator is safe for concurrent use
+// and lock-free.
+//
+// The 80 random bits are made of a 16-bit sequence, randomly seeded every
+// millisecond and incremented within it, and 64 bits from crypto/rand.
+// Like UUIDv7Generator, the creation time can be read from the ID (see ParseULID).
+//
+// Example usage:
+//
+//	app.Use(fiber.New(fiber.Config{
+//	    Config: goctxid.Config{
+//	        Generator: goctxid.ULIDGenerator,
+//	    },
+//	}))
+func ULIDGenerator() string {
+	var r [10]byte
+	_, _ = rand.Read(r[:])
+
+	var id ULID
+	binary.BigEndian.PutUint64(id[:8], nextULIDHigh(binary.BigEndian.Uint16(r[:2])))
+	copy(id[8:], r[2:])
+	return id.String()
+}
+
+// nextULIDHigh returns the next upper 64 bits of a ULID.
+// seed is used as the sequence when a new millisecond starts; its top bit is
+// cleared so that at least 32768 IDs fit in a millisecond before the sequence
+// overflows into the timestamp (the timestamp then runs slightly ahead).
+func nextULIDHigh(seed uint16) uint64 {
+	for {
+		last := ulidState.Load()
+		next := last + 1
+		if ms := uint64(time.Now().UnixMilli()); ms > last>>16 {
+			next = ms<<16 | uint64(seed&0x7FFF)
+		}
+		if ulidState.CompareAndSwap(last, next) {
+			return next
+		}
+	}
+}
+
+// ParseULID parses a 26-character Crockford base32 ULID.
+// Lowercase letters and the I/L/O aliases are accepted.
+// Returns ErrInvalidULID for any other ID.
+func ParseULID(id string) (ULID, error) {
+	var u ULID
+	if !ValidateULID(id) {
+		return u, ErrInvalidULID
+	}
+
+	var v [26]byte
+	for i := 0; i < len(v); i++ {
+		v[i] = crockfordDecode[id[i]]
+	}
+
+	// 26 characters * 5 bits = 130 bits; the first character only has 3 bits
+	u[0] = v[0]<<5 | v[1]
+	u[1] = v[2]<<3 | v[3]>>2
+	u[2] = v[3]<<6 | v[4]<<1 | v[5]>>4
+	u[3] = v[5]<<4 | v[6]>>1
+	u[4] = v[6]<<7 | v[7]<<2 | v[8]>>3
+	u[5] = v[8]<<5 | v[9]
+	u[6] = v[10]<<3 | v[11]>>2
+	u[7] = v[11]<<6 | v[12]<<1 | v[13]>>4
+	u[8] = v[13]<<4 | v[14]>>1
+	u[9] = v[14]<<7 | v[15]<<2 | v[16]>>3
+	u[10] = v[16]<<5 | v[17]
+	u[11] = v[18]<<3 | v[19]>>2
+	u[12] = v[19]<<6 | v[20]<<1 | v[21]>>4
+	u[13] = v[21]<<4 | v[22]>>1
+	u[14] = v[22]<<7 | v[23]<<2 | v[24]>>3
+	u[15] = v[24]<<5 | v[25]
+	return u, nil
+}
+
+// Time returns the creation time embedded in the ULID, with millisecond precision
+func (u ULID) Time() time.Time {
+	var ms int64
+	for _, b := range u[0:6] {
+		ms = ms<<8 | int64(b)
+	}
+	return time.UnixMilli(ms)
+}
+
+// String returns the canonical 26-character uppercase Crockford base32 form
+func (u ULID) String() string {
+	const a = crockfordAlphabet
+	s := [26]byte{
+		a[u[0]>>5],
+		a[u[0]&0x1F],
+		a[u[1]>>3],
+		a[(u[1]&0x07)<<2|u[2]>>6],
+		a[(u[2]>>1)&0x1F],
+		a[(u[2]&0x01)<<4|u[3]>>4],
+		a[(u[3]&0x0F)<<1|u[4]>>7],
+		a[(u[4]>>2)&0x1F],
+		a[(u[4]&0x03)<<3|u[5]>>5],
+		a[u[5]&0x1F],
+		a[u[6]>>3],
+		a[(u[6]&0x07)<<2|u[7]>>6],
+		a[(u[7]>>1)&0x1F],
+		a[(u[7]&0x01)<<4|u[8]>>4],
+		a[(u[8]&0x0F)<<1|u[9]>>7],
+		a[(u[9]>>2)&0x1F],
+		a[(u[9]&0x03)<<3|u[10]>>5],
+		a[u[10]&0x1F],
+		a[u[11]>>3],
+		a[(u[11]&0x07)<<2|u[12]>>6],
+		a[(u[12]>>1)&0x1F],
+		a[(u[12]&0x01)<<4|u[13]>>4],
+		a[(u[13]&0x0F)<<1|u[14]>>7],
+		a[(u[14]>>2)&0x1F],
+		a[(u[14]&0x03)<<3|u[15]>>5],
+		a[u[15]&0x1F],
+	}
+	return string(s[:])
+}
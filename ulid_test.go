@@ -0,0 +1,175 @@
+package goctxid
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestULIDGenerator(t *testing.T) {
+	t.Run("generates ULID", func(t *testing.T) {
+		id := ULIDGenerator()
+		if len(id) != 26 {
+			t.Fatalf("Expected 26 characters, got %d in %s", len(id), id)
+		}
+		if !ValidateULID(id) {
+			t.Fatalf("ULIDGenerator returned invalid ULID: %s", id)
+		}
+		u, err := ParseULID(id)
+		if err != nil {
+			t.Fatalf("ParseULID(%s) error = %v", id, err)
+		}
+		if u.String() != id {
+			t.Errorf("Round trip mismatch: %s != %s", u.String(), id)
+		}
+	})
+
+	t.Run("generates increasing IDs", func(t *testing.T) {
+		// Many IDs share the same millisecond, so this checks monotonicity within it
+		prev := ULIDGenerator()
+		for i := 0; i < 10000; i++ {
+			id := ULIDGenerator()
+			if id <= prev {
+				t.Fatalf("IDs are not increasing: %s <= %s", id, prev)
+			}
+			prev = id
+		}
+	})
+
+	t.Run("is thread-safe", func(t *testing.T) {
+		const numGoroutines = 100
+		const idsPerGoroutine = 100
+
+		var mu sync.Mutex
+		seen := make(map[string]bool, numGoroutines*idsPerGoroutine)
+
+		var wg sync.WaitGroup
+		for i := 0; i < numGoroutines; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ids := make([]string, idsPerGoroutine)
+				for j := range ids {
+					ids[j] = ULIDGenerator()
+				}
+				// Each goroutine must see its own IDs in order
+				if !sort.StringsAreSorted(ids) {
+					t.Error("IDs generated by one goroutine are not sorted")
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, id := range ids {
+					if seen[id] {
+						t.Errorf("Duplicate ID found: %s", id)
+					}
+					seen[id] = true
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("embeds generation time", func(t *testing.T) {
+		before := time.Now().Truncate(time.Millisecond)
+		id := ULIDGenerator()
+		after := time.Now()
+
+		u, err := ParseULID(id)
+		if err != nil {
+			t.Fatalf("ParseULID(%s) error = %v", id, err)
+		}
+		// The monotonic sequence can push the timestamp slightly ahead
+		if got := u.Time(); got.Before(before) || got.After(after.Add(time.Millisecond)) {
+			t.Errorf("ParseULID(%s).Time() = %v, want between %v and %v", id, got, before, after)
+		}
+	})
+}
+
+func TestParseULID(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		expectedTime time.Time
+		expectedStr  string
+		expectedErr  error
+	}{
+		{
+			name:         "spec example",
+			id:           "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+			expectedTime: time.UnixMilli(1469922850259),
+			expectedStr:  "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		},
+		{
+			name:         "lowercase",
+			id:           "01arz3ndektsv4rrffq69g5fav",
+			expectedTime: time.UnixMilli(1469922850259),
+			expectedStr:  "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		},
+		{
+			name:         "aliases",
+			id:           "OIARZ3NDEKTSV4RRFFQ69G5FAV",
+			expectedTime: time.UnixMilli(1469922850259),
+			expectedStr:  "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		},
+		{
+			name:         "maximum",
+			id:           "7ZZZZZZZZZZZZZZZZZZZZZZZZZ",
+			expectedTime: time.UnixMilli(1<<48 - 1),
+			expectedStr:  "7ZZZZZZZZZZZZZZZZZZZZZZZZZ",
+		},
+		{
+			name:        "overflow",
+			id:          "8ZZZZZZZZZZZZZZZZZZZZZZZZZ",
+			expectedErr: ErrInvalidULID,
+		},
+		{
+			name:        "invalid character",
+			id:          "01ARZ3NDEKTSV4RRFFQ69G5FAU",
+			expectedErr: ErrInvalidULID,
+		},
+		{
+			name:        "too short",
+			id:          "01ARZ3NDEKTSV4RRFFQ69G5FA",
+			expectedErr: ErrInvalidULID,
+		},
+		{
+			name:        "UUID",
+			id:          "123e4567-e89b-42d3-a456-426614174000",
+			expectedErr: ErrInvalidULID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := ParseULID(tt.id)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("ParseULID(%s) error = %v, want %v", tt.id, err, tt.expectedErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := u.Time(); !got.Equal(tt.expectedTime) {
+				t.Errorf("ParseULID(%s).Time() = %v, want %v", tt.id, got, tt.expectedTime)
+			}
+			if got := u.String(); got != tt.expectedStr {
+				t.Errorf("ParseULID(%s).String() = %s, want %s", tt.id, got, tt.expectedStr)
+			}
+		})
+	}
+}
+
+func BenchmarkULIDGenerator(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = ULIDGenerator()
+	}
+}
+
+func BenchmarkULIDGeneratorParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = ULIDGenerator()
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package goctxid
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestBufferedGenerator(t *testing.T) {
+	t.Run("generates UUID v4", func(t *testing.T) {
+		// Generate more IDs than fit in one buffer to cover refills
+		for i := 0; i < 1000; i++ {
+			id := BufferedGenerator()
+			u, err := uuid.Parse(id)
+			if err != nil {
+				t.Fatalf("BufferedGenerator returned invalid UUID %s: %v", id, err)
+			}
+			if u.Version() != 4 || u.Variant() != uuid.RFC4122 {
+				t.Fatalf("Expected UUID v4 with RFC 4122 variant, got %s", id)
+			}
+		}
+	})
+
+	t.Run("is thread-safe", func(t *testing.T) {
+		const numGoroutines = 100
+		const idsPerGoroutine = 1000
+
+		var mu sync.Mutex
+		seen := make(map[string]bool, numGoroutines*idsPerGoroutine)
+
+		var wg sync.WaitGroup
+		for i := 0; i < numGoroutines; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ids := make([]string, idsPerGoroutine)
+				for j := range ids {
+					ids[j] = BufferedGenerator()
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, id := range ids {
+					if seen[id] {
+						t.Errorf("Duplicate ID found: %s", id)
+					}
+					seen[id] = true
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+// Compare with DefaultGenerator under load:
+//
+//	go test . -run xxx -bench 'BufferedGenerator|DefaultGenerator' -cpu 1,4,16
+func BenchmarkBufferedGenerator(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = BufferedGenerator()
+	}
+}
+
+func BenchmarkBufferedGeneratorParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = BufferedGenerator()
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package goctxid
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotUUIDv7 is returned by UUIDv7Time when the ID is not a version 7 UUID
+var ErrNotUUIDv7 = errors.New("goctxid: not a UUIDv7")
+
+// UUIDv7Generator generates time-ordered UUID v7 correlation IDs (RFC 9562).
+//
+// The first 48 bits are the Unix time in milliseconds, so IDs sort by creation
+// time. This keeps B-tree indexes compact and makes log IDs roughly sortable.
+// IDs are strictly increasing within a process, including within the same
+// millisecond, and the generator is safe for concurrent use.
+//
+// Note that the creation time of the request can be read from the ID
+// (see UUIDv7Time).
+//
+// Example usage:
+//
+//	app.Use(fiber.New(fiber.Config{
+//	    Config: goctxid.Config{
+//	        Generator: goctxid.UUIDv7Generator,
+//	    },
+//	}))
+func UUIDv7Generator() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// UUIDv7Time returns the creation time embedded in a UUID v7 correlation ID,
+// with millisecond precision. Returns ErrNotUUIDv7 for any other ID.
+func UUIDv7Time(id string) (time.Time, error) {
+	if !ValidateUUID(id) {
+		return time.Time{}, ErrNotUUIDv7
+	}
+	u, err := uuid.Parse(id)
+	if err != nil || u.Version() != 7 || u.Variant() != uuid.RFC4122 {
+		return time.Time{}, ErrNotUUIDv7
+	}
+
+	var ms int64
+	for _, b := range u[0:6] {
+		ms = ms<<8 | int64(b)
+	}
+	return time.UnixMilli(ms), nil
+}
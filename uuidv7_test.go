@@ -0,0 +1,150 @@
+package goctxid
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUUIDv7Generator(t *testing.T) {
+	t.Run("generates UUID v7", func(t *testing.T) {
+		id := UUIDv7Generator()
+		if !ValidateUUID(id) {
+			t.Fatalf("UUIDv7Generator returned invalid UUID: %s", id)
+		}
+		if id[14] != '7' {
+			t.Errorf("Expected version 7, got %c in %s", id[14], id)
+		}
+		if v := id[19]; v != '8' && v != '9' && v != 'a' && v != 'b' {
+			t.Errorf("Expected RFC 9562 variant, got %c in %s", v, id)
+		}
+	})
+
+	t.Run("generates increasing IDs", func(t *testing.T) {
+		// Many IDs share the same millisecond, so this checks monotonicity within it
+		prev := UUIDv7Generator()
+		for i := 0; i < 10000; i++ {
+			id := UUIDv7Generator()
+			if id <= prev {
+				t.Fatalf("IDs are not increasing: %s <= %s", id, prev)
+			}
+			prev = id
+		}
+	})
+
+	t.Run("is thread-safe", func(t *testing.T) {
+		const numGoroutines = 100
+		const idsPerGoroutine = 100
+
+		var mu sync.Mutex
+		seen := make(map[string]bool, numGoroutines*idsPerGoroutine)
+
+		var wg sync.WaitGroup
+		for i := 0; i < numGoroutines; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ids := make([]string, idsPerGoroutine)
+				for j := range ids {
+					ids[j] = UUIDv7Generator()
+				}
+				// Each goroutine must see its own IDs in order
+				if !sort.StringsAreSorted(ids) {
+					t.Error("IDs generated by one goroutine are not sorted")
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				for _, id := range ids {
+					if seen[id] {
+						t.Errorf("Duplicate ID found: %s", id)
+					}
+					seen[id] = true
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestUUIDv7Time(t *testing.T) {
+	t.Run("extracts generation time", func(t *testing.T) {
+		before := time.Now().Truncate(time.Millisecond)
+		id := UUIDv7Generator()
+		after := time.Now()
+
+		got, err := UUIDv7Time(id)
+		if err != nil {
+			t.Fatalf("UUIDv7Time(%s) error = %v", id, err)
+		}
+		// The monotonic sequence can push the timestamp slightly ahead
+		if got.Before(before) || got.After(after.Add(time.Millisecond)) {
+			t.Errorf("UUIDv7Time(%s) = %v, want between %v and %v", id, got, before, after)
+		}
+	})
+
+	tests := []struct {
+		name        string
+		id          string
+		expected    time.Time
+		expectedErr error
+	}{
+		{
+			name:     "RFC 9562 example",
+			id:       "017f22e2-79b0-7cc3-98c4-dc0c0c07398f",
+			expected: time.UnixMilli(0x017f22e279b0),
+		},
+		{
+			name:     "uppercase",
+			id:       "017F22E2-79B0-7CC3-98C4-DC0C0C07398F",
+			expected: time.UnixMilli(0x017f22e279b0),
+		},
+		{
+			name:        "UUID v4",
+			id:          "123e4567-e89b-42d3-a456-426614174000",
+			expectedErr: ErrNotUUIDv7,
+		},
+		{
+			name:        "wrong variant",
+			id:          "017f22e2-79b0-7cc3-c8c4-dc0c0c07398f",
+			expectedErr: ErrNotUUIDv7,
+		},
+		{
+			name:        "not a UUID",
+			id:          "custom-id",
+			expectedErr: ErrNotUUIDv7,
+		},
+		{
+			name:        "UUID without dashes",
+			id:          "017f22e279b07cc398c4dc0c0c07398f",
+			expectedErr: ErrNotUUIDv7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UUIDv7Time(tt.id)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("UUIDv7Time(%s) error = %v, want %v", tt.id, err, tt.expectedErr)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("UUIDv7Time(%s) = %v, want %v", tt.id, got, tt.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkUUIDv7Generator(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = UUIDv7Generator()
+	}
+}
+
+func BenchmarkUUIDv7GeneratorParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = UUIDv7Generator()
+		}
+	})
+}
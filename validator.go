@@ -0,0 +1,316 @@
+package goctxid
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+)
+
+// InvalidPolicy decides what the middleware does with an inbound correlation ID
+// that fails Config.Validator
+type InvalidPolicy int
+
+const (
+	// InvalidRegenerate discards the inbound ID and generates a new one (default)
+	InvalidRegenerate InvalidPolicy = iota
+
+	// InvalidReject aborts the request with 400 Bad Request
+	InvalidReject
+
+	// InvalidKeep keeps the inbound ID but flags it as invalid.
+	// Handlers can check the flag with IsInvalid.
+	InvalidKeep
+)
+
+// ErrInvalidID is returned by Config.Resolve when an inbound correlation ID
+// fails validation and the policy is InvalidReject
+var ErrInvalidID = errors.New("goctxid: invalid correlation ID")
+
+// Resolution is the outcome of resolving the correlation ID of a request
+type Resolution struct {
+	// ID is the correlation ID to store in the context and send back to the client
+	ID string
+
+	// Invalid is true when ID came from the request, failed validation
+	// and was kept because of InvalidKeep
+	Invalid bool
+
+	// Origin tells where ID came from
+	Origin Origin
+
+	// HeaderKey is the inbound header ID was read from
+	// (only set for OriginHeader and OriginTraceParent)
+	HeaderKey string
+
+	// RequestID is the per-service request ID to store in the context and send
+	// back to the client. Only set when Config.RequestIDGenerator is configured.
+	RequestID string
+
+	// TraceParent is the traceparent to send back to the client and to store in
+	// the context. Only set when Config.EmitTraceParent is enabled.
+	TraceParent TraceParent
+}
+
+// Resolve decides which correlation ID a request should use.
+//
+// header is used to read inbound header values (e.g. c.GetHeader in Gin).
+// The inbound ID is read from InboundHeaderKeys in order, or from HeaderKey,
+// unless the request is not trusted (see TrustedProxies and TrustInbound).
+// With Signer, inbound IDs without a valid tag are ignored and new IDs are signed.
+// The inbound ID is checked with Validator and handled according to OnInvalid.
+// When there is no usable inbound ID, the ID is derived from the traceparent
+// header (with UseTraceParent and no Signer) or generated with RequestGenerator
+// or Generator.
+// ErrInvalidID is returned when the request must be rejected.
+//
+// This method is intended for adapters and custom middleware. The config must
+// already have its defaults filled in (HeaderKey and Generator set).
+// Use ResolveRequest to pass the request to RequestGenerator and the trust
+// policy; with Resolve, requests are untrusted when a trust policy is set.
+func (c Config) Resolve(header func(key string) string) (Resolution, error) {
+	return c.ResolveRequest(context.Background(), RequestInfo{Header: header})
+}
+
+// ResolveRequest is like Resolve, but passes the request context and info to
+// RequestGenerator. Headers are read with r.Header, which must be set.
+func (c Config) ResolveRequest(ctx context.Context, r RequestInfo) (Resolution, error) {
+	var res Resolution
+	header := r.Header
+
+	// Only look at traceparent when it is used
+	var inbound TraceParent
+	var hasInbound bool
+	if c.UseTraceParent || c.EmitTraceParent {
+		var err error
+		inbound, err = ParseTraceParent(header(TraceParentHeader))
+		hasInbound = err == nil
+	}
+
+	// Inbound IDs from untrusted peers are ignored, not validated
+	var key, id, unsigned string
+	trusted := c.trusts(r)
+	if trusted {
+		key, id = c.inboundID(header)
+	}
+	// Forged IDs are always regenerated, whatever OnInvalid says
+	if id != "" && c.Signer != nil {
+		var ok bool
+		if unsigned, ok = c.Signer.Verify(id); !ok {
+			id = ""
+		}
+	}
+	// Validators check the ID itself, not its tag
+	validated := id
+	if c.Signer != nil {
+		validated = unsigned
+	}
+	if id != "" && c.Validator != nil && !c.Validator(validated) {
+		switch c.OnInvalid {
+		case InvalidReject:
+			return Resolution{}, ErrInvalidID
+		case InvalidKeep:
+			res.Invalid = true
+		default:
+			id = ""
+		}
+	}
+
+	switch {
+	case id != "":
+		res.ID, res.Origin, res.HeaderKey = id, OriginHeader, key
+	// A traceparent carries no tag, so with a Signer it must not choose the ID
+	case c.UseTraceParent && c.Signer == nil && hasInbound && trusted:
+		res.ID, res.Origin, res.HeaderKey = inbound.TraceIDString(), OriginTraceParent, TraceParentHeader
+	case c.RequestGenerator != nil:
+		res.ID, res.Origin = c.RequestGenerator(ctx, r), OriginGenerated
+	default:
+		res.ID, res.Origin = c.Generator(), OriginGenerated
+	}
+
+	// Only IDs this service minted are signed; verified inbound IDs keep their tag
+	if c.Signer == nil {
+		unsigned = res.ID
+	} else if res.Origin != OriginHeader {
+		unsigned, res.ID = res.ID, c.Signer.Sign(res.ID)
+	}
+
+	if c.RequestIDGenerator != nil {
+		res.RequestID = c.RequestIDGenerator()
+	}
+
+	if c.EmitTraceParent {
+		// Untrusted peers cannot choose the trace either
+		if hasInbound && trusted {
+			res.TraceParent = inbound.Child()
+		} else {
+			// Derived from the ID without its tag
+			res.TraceParent = NewTraceParent(unsigned)
+		}
+	}
+
+	return res, nil
+}
+
+// RequestInfo is a framework-neutral view of the request, filled in by every
+// adapter and passed to Config.RequestGenerator
+type RequestInfo struct {
+	// Method is the HTTP method (always "POST" for gRPC)
+	Method string
+
+	// Path is the URL path, or the full RPC method (/package.service/method) for gRPC
+	Path string
+
+	// RemoteAddr is the network address of the client, usually "IP:port"
+	RemoteAddr string
+
+	// RemoteIP is the IP address of the direct peer (never taken from
+	// X-Forwarded-For or similar headers), or the zero value if unknown
+	RemoteIP netip.Addr
+
+	// Header returns the value of a request header (gRPC metadata for gRPC),
+	// or an empty string if it is not set
+	Header func(key string) string
+}
+
+// inboundID returns the first non-empty inbound correlation ID header value
+// and the header it was read from
+func (c Config) inboundID(header func(key string) string) (string, string) {
+	if len(c.InboundHeaderKeys) == 0 {
+		return c.HeaderKey, header(c.HeaderKey)
+	}
+	for _, key := range c.InboundHeaderKeys {
+		if id := header(key); id != "" {
+			return key, id
+		}
+	}
+	return "", ""
+}
+
+// Info returns the correlation ID and where it came from
+func (r Resolution) Info() IDInfo {
+	return IDInfo{ID: r.ID, Origin: r.Origin, HeaderKey: r.HeaderKey, Invalid: r.Invalid}
+}
+
+// NewContext creates a new context carrying the resolved correlation ID,
+// its origin (see FromContextWithInfo) and, if set, the request ID and traceparent
+func (r Resolution) NewContext(ctx context.Context) context.Context {
+	ctx = NewContext(ctx, r.ID)
+	ctx = context.WithValue(ctx, infoCtxKey, r.Info())
+	if r.RequestID != "" {
+		ctx = NewRequestIDContext(ctx, r.RequestID)
+	}
+	if r.TraceParent.IsValid() {
+		ctx = NewTraceParentContext(ctx, r.TraceParent)
+	}
+	return ctx
+}
+
+// IsInvalid reports whether the correlation ID in the context came from the
+// request and failed validation (only possible with InvalidKeep)
+func IsInvalid(ctx context.Context) bool {
+	info, _ := FromContextWithInfo(ctx)
+	return info.Invalid
+}
+
+// ValidateUUID reports whether id is a UUID in the canonical
+// 8-4-4-4-12 hexadecimal form (any version)
+func ValidateUUID(id string) bool {
+	if len(id) != 36 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if id[i] != '-' {
+				return false
+			}
+		default:
+			if !isHex(id[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ValidateULID reports whether id is a 26-character Crockford base32 ULID.
+// Use ParseULID to also decode it.
+func ValidateULID(id string) bool {
+	if len(id) != 26 {
+		return false
+	}
+	// The first character only carries 3 bits of the 48-bit timestamp
+	if crockfordDecode[id[0]] > 7 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if crockfordDecode[id[i]] == 0xFF {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidatePrintableASCII reports whether id only contains printable ASCII
+// characters (0x20-0x7E). This rejects control characters such as CR and LF
+// that could be used for log injection.
+func ValidatePrintableASCII(id string) bool {
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x20 || id[i] > 0x7E {
+			return false
+		}
+	}
+	return true
+}
+
+// MaxLength returns a validator that rejects IDs longer than n bytes
+func MaxLength(n int) func(id string) bool {
+	return func(id string) bool {
+		return len(id) <= n
+	}
+}
+
+// CombineValidators returns a validator that accepts an ID only if
+// every given validator accepts it
+//
+// Example usage:
+//
+//	goctxid.Config{
+//	    Validator: goctxid.CombineValidators(
+//	        goctxid.MaxLength(64),
+//	        goctxid.ValidatePrintableASCII,
+//	    ),
+//	}
+func CombineValidators(validators ...func(id string) bool) func(id string) bool {
+	return func(id string) bool {
+		for _, v := range validators {
+			if !v(id) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// isHex reports whether b is a hexadecimal digit
+func isHex(b byte) bool {
+	return ('0' <= b && b <= '9') || ('a' <= b && b <= 'f') || ('A' <= b && b <= 'F')
+}
+
+// crockfordDecode maps Crockford base32 characters to their value (0xFF = invalid).
+// Lowercase letters are accepted, as are the I/L (1) and O (0) aliases.
+var crockfordDecode = func() [256]byte {
+	var t [256]byte
+	for i := range t {
+		t[i] = 0xFF
+	}
+	for i := 0; i < len(crockfordAlphabet); i++ {
+		t[crockfordAlphabet[i]] = byte(i)
+		if c := crockfordAlphabet[i]; c >= 'A' && c <= 'Z' {
+			t[c+'a'-'A'] = byte(i)
+		}
+	}
+	t['I'], t['i'], t['L'], t['l'] = 1, 1, 1, 1
+	t['O'], t['o'] = 0, 0
+	return t
+}()
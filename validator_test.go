@@ -0,0 +1,361 @@
+package goctxid
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	generator := func() string { return "generated-id" }
+	rejectAll := func(string) bool { return false }
+
+	tests := []struct {
+		name            string
+		config          Config
+		inbound         string
+		expectedID      string
+		expectedInvalid bool
+		expectedErr     error
+	}{
+		{
+			name:       "generates ID when header is missing",
+			config:     Config{},
+			inbound:    "",
+			expectedID: "generated-id",
+		},
+		{
+			name:       "uses inbound ID without validator",
+			config:     Config{},
+			inbound:    "client-id",
+			expectedID: "client-id",
+		},
+		{
+			name:       "uses inbound ID that passes validation",
+			config:     Config{Validator: MaxLength(16)},
+			inbound:    "client-id",
+			expectedID: "client-id",
+		},
+		{
+			name:       "regenerates invalid ID by default",
+			config:     Config{Validator: rejectAll},
+			inbound:    "client-id",
+			expectedID: "generated-id",
+		},
+		{
+			name:       "regenerates invalid ID with InvalidRegenerate",
+			config:     Config{Validator: rejectAll, OnInvalid: InvalidRegenerate},
+			inbound:    "client-id",
+			expectedID: "generated-id",
+		},
+		{
+			name:        "rejects invalid ID with InvalidReject",
+			config:      Config{Validator: rejectAll, OnInvalid: InvalidReject},
+			inbound:     "client-id",
+			expectedID:  "",
+			expectedErr: ErrInvalidID,
+		},
+		{
+			name:            "keeps and flags invalid ID with InvalidKeep",
+			config:          Config{Validator: rejectAll, OnInvalid: InvalidKeep},
+			inbound:         "client-id",
+			expectedID:      "client-id",
+			expectedInvalid: true,
+		},
+		{
+			name:       "does not validate generated IDs",
+			config:     Config{Validator: rejectAll, OnInvalid: InvalidReject},
+			inbound:    "",
+			expectedID: "generated-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.config
+			cfg.HeaderKey = DefaultHeaderKey
+			cfg.Generator = generator
+
+			res, err := cfg.Resolve(func(key string) string {
+				if key != DefaultHeaderKey {
+					t.Errorf("Resolve() read header %q, want %q", key, DefaultHeaderKey)
+				}
+				return tt.inbound
+			})
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Resolve() error = %v, want %v", err, tt.expectedErr)
+			}
+			if res.ID != tt.expectedID {
+				t.Errorf("Resolve() ID = %v, want %v", res.ID, tt.expectedID)
+			}
+			if res.Invalid != tt.expectedInvalid {
+				t.Errorf("Resolve() Invalid = %v, want %v", res.Invalid, tt.expectedInvalid)
+			}
+		})
+	}
+}
+
+func TestResolveInboundHeaderKeys(t *testing.T) {
+	generator := func() string { return "generated-id" }
+	keys := []string{"X-Request-ID", "X-Correlation-ID", "Request-Id"}
+
+	tests := []struct {
+		name       string
+		config     Config
+		headers    map[string]string
+		expectedID string
+	}{
+		{
+			name:       "uses first header in the list",
+			config:     Config{InboundHeaderKeys: keys},
+			headers:    map[string]string{"X-Request-ID": "request-id", "X-Correlation-ID": "correlation-id"},
+			expectedID: "request-id",
+		},
+		{
+			name:       "falls back to later headers",
+			config:     Config{InboundHeaderKeys: keys},
+			headers:    map[string]string{"Request-Id": "fallback-id"},
+			expectedID: "fallback-id",
+		},
+		{
+			name:       "generates ID when no header is set",
+			config:     Config{InboundHeaderKeys: keys},
+			headers:    map[string]string{},
+			expectedID: "generated-id",
+		},
+		{
+			name:       "ignores HeaderKey when not listed",
+			config:     Config{InboundHeaderKeys: []string{"X-Request-ID"}},
+			headers:    map[string]string{"X-Correlation-ID": "correlation-id"},
+			expectedID: "generated-id",
+		},
+		{
+			name:       "reads only HeaderKey without list",
+			config:     Config{},
+			headers:    map[string]string{"X-Request-ID": "request-id", "X-Correlation-ID": "correlation-id"},
+			expectedID: "correlation-id",
+		},
+		{
+			name:       "validates the selected header",
+			config:     Config{InboundHeaderKeys: keys, Validator: ValidateUUID},
+			headers:    map[string]string{"X-Request-ID": "not-a-uuid", "X-Correlation-ID": "123e4567-e89b-12d3-a456-426614174000"},
+			expectedID: "generated-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.config
+			cfg.HeaderKey = DefaultHeaderKey
+			cfg.Generator = generator
+
+			res, err := cfg.Resolve(func(key string) string {
+				return tt.headers[key]
+			})
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if res.ID != tt.expectedID {
+				t.Errorf("Resolve() ID = %v, want %v", res.ID, tt.expectedID)
+			}
+		})
+	}
+}
+
+func TestResolveRequest(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "tenant-a")
+	info := RequestInfo{
+		Method:     "GET",
+		Path:       "/orders",
+		RemoteAddr: "192.0.2.1:1234",
+		Header: func(key string) string {
+			if key == "X-Region" {
+				return "eu"
+			}
+			return ""
+		},
+	}
+
+	var got RequestInfo
+	cfg := Config{
+		HeaderKey: DefaultHeaderKey,
+		Generator: func() string { return "generated-id" },
+		RequestGenerator: func(ctx context.Context, r RequestInfo) string {
+			got = r
+			return r.Header("X-Region") + "-" + ctx.Value(ctxKey{}).(string)
+		},
+	}
+
+	t.Run("RequestGenerator takes precedence over Generator", func(t *testing.T) {
+		res, err := cfg.ResolveRequest(ctx, info)
+		if err != nil {
+			t.Fatalf("ResolveRequest() error = %v", err)
+		}
+		if res.ID != "eu-tenant-a" {
+			t.Errorf("ResolveRequest() ID = %v, want %v", res.ID, "eu-tenant-a")
+		}
+		if got.Method != info.Method || got.Path != info.Path || got.RemoteAddr != info.RemoteAddr {
+			t.Errorf("RequestGenerator got %+v, want %+v", got, info)
+		}
+	})
+
+	t.Run("RequestGenerator is not used for valid inbound IDs", func(t *testing.T) {
+		got = RequestInfo{}
+		r := info
+		r.Header = func(key string) string { return "client-id" }
+		res, err := cfg.ResolveRequest(ctx, r)
+		if err != nil {
+			t.Fatalf("ResolveRequest() error = %v", err)
+		}
+		if res.ID != "client-id" {
+			t.Errorf("ResolveRequest() ID = %v, want %v", res.ID, "client-id")
+		}
+		if got.Header != nil {
+			t.Error("RequestGenerator should not be called")
+		}
+	})
+
+	t.Run("Generator is used without RequestGenerator", func(t *testing.T) {
+		c := cfg
+		c.RequestGenerator = nil
+		res, _ := c.ResolveRequest(ctx, info)
+		if res.ID != "generated-id" {
+			t.Errorf("ResolveRequest() ID = %v, want %v", res.ID, "generated-id")
+		}
+	})
+}
+
+func TestResolutionNewContext(t *testing.T) {
+	t.Run("stores valid ID without flag", func(t *testing.T) {
+		ctx := Resolution{ID: "valid-id"}.NewContext(context.Background())
+
+		if id := MustFromContext(ctx); id != "valid-id" {
+			t.Errorf("MustFromContext() = %v, want %v", id, "valid-id")
+		}
+		if IsInvalid(ctx) {
+			t.Error("IsInvalid() = true, want false")
+		}
+	})
+
+	t.Run("stores invalid ID with flag", func(t *testing.T) {
+		ctx := Resolution{ID: "bad id", Invalid: true}.NewContext(context.Background())
+
+		if id := MustFromContext(ctx); id != "bad id" {
+			t.Errorf("MustFromContext() = %v, want %v", id, "bad id")
+		}
+		if !IsInvalid(ctx) {
+			t.Error("IsInvalid() = false, want true")
+		}
+	})
+
+	t.Run("empty context is not flagged", func(t *testing.T) {
+		if IsInvalid(context.Background()) {
+			t.Error("IsInvalid() = true, want false")
+		}
+	})
+}
+
+func TestValidateUUID(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected bool
+	}{
+		{DefaultGenerator(), true},
+		{"123e4567-e89b-12d3-a456-426614174000", true},
+		{"123E4567-E89B-12D3-A456-426614174000", true},
+		{"123e4567e89b12d3a456426614174000", false},
+		{"123e4567-e89b-12d3-a456-42661417400", false},
+		{"123e4567-e89b-12d3-a456_426614174000", false},
+		{"123e4567-e89b-12d3-a456-42661417400g", false},
+		{"{123e4567-e89b-12d3-a456-426614174000}", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidateUUID(tt.id); got != tt.expected {
+			t.Errorf("ValidateUUID(%q) = %v, want %v", tt.id, got, tt.expected)
+		}
+	}
+}
+
+func TestValidateULID(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected bool
+	}{
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAV", true},
+		{"01arz3ndektsv4rrffq69g5fav", true},
+		{"OIARZ3NDEKTSV4RRFFQ69G5FAV", true},
+		{"7ZZZZZZZZZZZZZZZZZZZZZZZZZ", true},
+		{"8ZZZZZZZZZZZZZZZZZZZZZZZZZ", false},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FA", false},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAVX", false},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAU", false},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FA-", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidateULID(tt.id); got != tt.expected {
+			t.Errorf("ValidateULID(%q) = %v, want %v", tt.id, got, tt.expected)
+		}
+	}
+}
+
+func TestValidatePrintableASCII(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected bool
+	}{
+		{"abc-123_XYZ", true},
+		{"with space", true},
+		{"~!@#$%^&*()", true},
+		{"", true},
+		{"line\nbreak", false},
+		{"carriage\rreturn", false},
+		{"tab\tseparated", false},
+		{"null\x00byte", false},
+		{"del\x7f", false},
+		{"ünicode", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidatePrintableASCII(tt.id); got != tt.expected {
+			t.Errorf("ValidatePrintableASCII(%q) = %v, want %v", tt.id, got, tt.expected)
+		}
+	}
+}
+
+func TestMaxLength(t *testing.T) {
+	validate := MaxLength(8)
+
+	if !validate("") {
+		t.Error("MaxLength(8) rejected empty ID")
+	}
+	if !validate("12345678") {
+		t.Error("MaxLength(8) rejected 8-byte ID")
+	}
+	if validate("123456789") {
+		t.Error("MaxLength(8) accepted 9-byte ID")
+	}
+}
+
+func TestCombineValidators(t *testing.T) {
+	validate := CombineValidators(MaxLength(40), ValidatePrintableASCII)
+
+	if !validate("short-id") {
+		t.Error("CombineValidators() rejected an ID accepted by every validator")
+	}
+	if validate(strings.Repeat("a", 41)) {
+		t.Error("CombineValidators() accepted an ID rejected by MaxLength")
+	}
+	if validate("bad\nid") {
+		t.Error("CombineValidators() accepted an ID rejected by ValidatePrintableASCII")
+	}
+	if !CombineValidators()("anything") {
+		t.Error("CombineValidators() with no validators should accept every ID")
+	}
+}
@@ -0,0 +1,196 @@
+// Package zapctx integrates goctxid with go.uber.org/zap.
+//
+// There are three ways to log the correlation ID:
+//
+//	// 1. A logger enriched from the context (context-based adapters)
+//	zapctx.Logger(ctx, logger).Info("Fetching user")
+//
+//	// 2. A field built from an ID (any adapter, including fibernative)
+//	logger.Info("Fetching user", zapctx.Field(fibernative.MustFromLocals(c)))
+//
+//	// 3. A core that resolves the ID from a context field at write time
+//	logger := zap.New(zapctx.NewCore(core))
+//	logger.Info("Fetching user", zapctx.Context(ctx))
+package zapctx
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/hiiamtin/goctxid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// DefaultKey is the default field key used for the correlation ID
+	DefaultKey = "correlation_id"
+
+	// contextFieldKey is the key of the placeholder field returned by Context
+	contextFieldKey = "goctxid_context"
+)
+
+// Config defines the options of the core created by NewCore
+type Config struct {
+	// Key is the field key used for the correlation ID
+	//
+	// Optional. Default: "correlation_id"
+	Key string
+}
+
+// configDefault is a helper function that merges the provided config with the default config
+func configDefault(config ...Config) Config {
+
+	var cfg Config
+
+	// If a config is provided, use it
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Check and fill in default values
+	if cfg.Key == "" {
+		cfg.Key = DefaultKey
+	}
+
+	return cfg
+}
+
+// Field returns a field carrying the correlation ID under DefaultKey.
+// An empty ID returns a field that is not logged.
+func Field(id string) zap.Field {
+	return FieldWithKey(DefaultKey, id)
+}
+
+// FieldWithKey is like Field but uses a custom key.
+func FieldWithKey(key, id string) zap.Field {
+	if id == "" {
+		return zap.Skip()
+	}
+	return zap.String(key, id)
+}
+
+// Logger returns logger enriched with the correlation ID from ctx under DefaultKey.
+// logger is returned unchanged when ctx carries no correlation ID.
+func Logger(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	id := goctxid.MustFromContext(ctx)
+	if id == "" {
+		return logger
+	}
+	return logger.With(Field(id))
+}
+
+// Context returns a placeholder field that a core created by NewCore replaces
+// with the correlation ID from ctx. Other cores ignore it.
+func Context(ctx context.Context) zap.Field {
+	return zap.Field{Key: contextFieldKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// NewCore wraps core so that fields created by Context are replaced with the
+// correlation ID of their context, both in logger.With and in log calls.
+func NewCore(core zapcore.Core, config ...Config) zapcore.Core {
+	cfg := configDefault(config...)
+	return &contextCore{Core: core, key: cfg.Key}
+}
+
+// contextCore is the zapcore.Core returned by NewCore
+type contextCore struct {
+	zapcore.Core
+	key string
+}
+
+// With implements zapcore.Core
+func (c *contextCore) With(fields []zapcore.Field) zapcore.Core {
+	return &contextCore{Core: c.Core.With(c.resolve(fields)), key: c.key}
+}
+
+// Check implements zapcore.Core. The wrapped core decides which cores write the
+// entry, so tees with per-core levels and samplers behave as without NewCore;
+// this core is registered in their place so that Write sees the fields.
+func (c *contextCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := c.Core.Check(entry, nil)
+	if inner == nil {
+		return checked
+	}
+	return checked.AddCore(entry, &checkedCore{contextCore: c, checked: inner})
+}
+
+// Write implements zapcore.Core
+func (c *contextCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.resolve(fields))
+}
+
+// checkedCore writes an entry to the cores selected by the wrapped core's Check
+type checkedCore struct {
+	*contextCore
+	checked *zapcore.CheckedEntry
+	errors  errorOutput
+}
+
+// Write implements zapcore.Core. Write errors of the wrapped cores are
+// returned, so the logger reports them to its own ErrorOutput.
+func (c *checkedCore) Write(_ zapcore.Entry, fields []zapcore.Field) error {
+	c.checked.ErrorOutput = &c.errors
+	c.checked.Write(c.resolve(fields)...)
+	return c.errors.err()
+}
+
+// errorOutput records the write error that CheckedEntry.Write reports to its
+// ErrorOutput as "<time> write error: <error>"
+type errorOutput struct {
+	msg []byte
+}
+
+// Write implements zapcore.WriteSyncer
+func (o *errorOutput) Write(p []byte) (int, error) {
+	o.msg = append(o.msg, p...)
+	return len(p), nil
+}
+
+// Sync implements zapcore.WriteSyncer
+func (o *errorOutput) Sync() error {
+	return nil
+}
+
+// err returns the recorded write error, or nil if there was none
+func (o *errorOutput) err() error {
+	if len(o.msg) == 0 {
+		return nil
+	}
+	msg := strings.TrimSuffix(string(o.msg), "\n")
+	if _, after, ok := strings.Cut(msg, " write error: "); ok {
+		msg = after
+	}
+	return errors.New(msg)
+}
+
+// resolve replaces the fields created by Context with correlation ID fields.
+// The caller's slice is copied before being modified.
+func (c *contextCore) resolve(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		ctx, ok := contextFromField(f)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, len(fields))
+			copy(out, fields)
+		}
+		out[i] = FieldWithKey(c.key, goctxid.MustFromContext(ctx))
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// contextFromField returns the context of a field created by Context
+func contextFromField(f zapcore.Field) (context.Context, bool) {
+	if f.Type != zapcore.SkipType || f.Key != contextFieldKey {
+		return nil, false
+	}
+	ctx, ok := f.Interface.(context.Context)
+	return ctx, ok
+}
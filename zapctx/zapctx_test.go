@@ -0,0 +1,369 @@
+package zapctx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hiiamtin/goctxid"
+	goctxid_fibernative "github.com/hiiamtin/goctxid/adapters/fibernative"
+	goctxid_nethttp "github.com/hiiamtin/goctxid/adapters/nethttp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newObservedLogger returns a logger recording its entries
+func newObservedLogger() (*zap.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return zap.New(core), logs
+}
+
+// newObservedContextLogger returns a logger using NewCore and recording its entries
+func newObservedContextLogger(config ...Config) (*zap.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	return zap.New(NewCore(core, config...)), logs
+}
+
+func TestField(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    zap.Field
+		expected map[string]any
+	}{
+		{
+			name:     "uses default key",
+			field:    Field("test-id"),
+			expected: map[string]any{DefaultKey: "test-id"},
+		},
+		{
+			name:     "uses custom key",
+			field:    FieldWithKey("request_id", "test-id"),
+			expected: map[string]any{"request_id": "test-id"},
+		},
+		{
+			name:     "skips empty ID",
+			field:    Field(""),
+			expected: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := newObservedLogger()
+			logger.Info("hello", tt.field)
+
+			got := logs.All()[0].ContextMap()
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Fields = %v, want %v", got, tt.expected)
+			}
+			for key, value := range tt.expected {
+				if got[key] != value {
+					t.Errorf("%s = %v, want %v", key, got[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestLogger(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		expectedID any
+	}{
+		{
+			name:       "adds ID from context",
+			ctx:        goctxid.NewContext(context.Background(), "test-id"),
+			expectedID: "test-id",
+		},
+		{
+			name:       "unchanged without ID",
+			ctx:        context.Background(),
+			expectedID: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := newObservedLogger()
+			Logger(tt.ctx, logger).Info("hello")
+
+			if got := logs.All()[0].ContextMap()[DefaultKey]; got != tt.expectedID {
+				t.Errorf("%s = %v, want %v", DefaultKey, got, tt.expectedID)
+			}
+		})
+	}
+
+	t.Run("returns same logger without ID", func(t *testing.T) {
+		logger, _ := newObservedLogger()
+		if Logger(context.Background(), logger) != logger {
+			t.Error("Expected the same logger")
+		}
+	})
+}
+
+func TestCore(t *testing.T) {
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	tests := []struct {
+		name     string
+		config   []Config
+		fields   []zap.Field
+		expected map[string]any
+	}{
+		{
+			name:     "replaces context field",
+			fields:   []zap.Field{Context(ctx), zap.String("user_id", "42")},
+			expected: map[string]any{DefaultKey: "test-id", "user_id": "42"},
+		},
+		{
+			name:     "uses custom key",
+			config:   []Config{{Key: "request_id"}},
+			fields:   []zap.Field{Context(ctx)},
+			expected: map[string]any{"request_id": "test-id"},
+		},
+		{
+			name:     "empty config uses default key",
+			config:   []Config{{}},
+			fields:   []zap.Field{Context(ctx)},
+			expected: map[string]any{DefaultKey: "test-id"},
+		},
+		{
+			name:     "skips context without ID",
+			fields:   []zap.Field{Context(context.Background())},
+			expected: map[string]any{},
+		},
+		{
+			name:     "ignores nil context",
+			fields:   []zap.Field{Context(nil)}, //nolint:staticcheck // testing nil context
+			expected: map[string]any{},
+		},
+		{
+			name:     "keeps other fields",
+			fields:   []zap.Field{zap.String("user_id", "42")},
+			expected: map[string]any{"user_id": "42"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := newObservedContextLogger(tt.config...)
+			logger.Info("hello", tt.fields...)
+
+			got := logs.All()[0].ContextMap()
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Fields = %v, want %v", got, tt.expected)
+			}
+			for key, value := range tt.expected {
+				if got[key] != value {
+					t.Errorf("%s = %v, want %v", key, got[key], value)
+				}
+			}
+		})
+	}
+}
+
+func TestCoreWith(t *testing.T) {
+	logger, logs := newObservedContextLogger()
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	logger.With(Context(ctx)).Info("hello")
+
+	if got := logs.All()[0].ContextMap()[DefaultKey]; got != "test-id" {
+		t.Errorf("%s = %v, want test-id", DefaultKey, got)
+	}
+}
+
+func TestCoreLevel(t *testing.T) {
+	inner, logs := observer.New(zapcore.InfoLevel)
+	core := NewCore(inner)
+
+	// zap.Logger skips disabled levels before calling Check, so call it directly
+	if ce := core.Check(zapcore.Entry{Level: zapcore.DebugLevel}, nil); ce != nil {
+		t.Error("Expected Debug to be filtered")
+	}
+	if ce := core.Check(zapcore.Entry{Level: zapcore.InfoLevel}, nil); ce == nil {
+		t.Error("Expected Info to be enabled")
+	}
+	if logs.Len() != 0 {
+		t.Errorf("Expected no entries, got %d", logs.Len())
+	}
+}
+
+func TestCoreTee(t *testing.T) {
+	debugCore, debugLogs := observer.New(zapcore.DebugLevel)
+	errorCore, errorLogs := observer.New(zapcore.ErrorLevel)
+	logger := zap.New(NewCore(zapcore.NewTee(debugCore, errorCore)))
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	logger.Debug("debug", Context(ctx))
+	logger.Error("error", Context(ctx))
+
+	if debugLogs.Len() != 2 {
+		t.Errorf("Debug core entries = %d, want 2", debugLogs.Len())
+	}
+	if errorLogs.Len() != 1 || errorLogs.All()[0].Message != "error" {
+		t.Fatalf("Error core entries = %v, want only the error entry", errorLogs.All())
+	}
+	if got := errorLogs.All()[0].ContextMap()[DefaultKey]; got != "test-id" {
+		t.Errorf("%s = %v, want test-id", DefaultKey, got)
+	}
+}
+
+func TestCoreSampler(t *testing.T) {
+	inner, logs := observer.New(zapcore.InfoLevel)
+	// Only the first entry with a given message is logged per tick
+	sampled := zapcore.NewSamplerWithOptions(inner, time.Hour, 1, 0)
+	logger := zap.New(NewCore(sampled))
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	for i := 0; i < 3; i++ {
+		logger.Info("hello", Context(ctx))
+	}
+
+	if logs.Len() != 1 {
+		t.Fatalf("Entries = %d, want 1 (sampled)", logs.Len())
+	}
+	if got := logs.All()[0].ContextMap()[DefaultKey]; got != "test-id" {
+		t.Errorf("%s = %v, want test-id", DefaultKey, got)
+	}
+}
+
+// failingCore is a core whose writes always fail
+type failingCore struct {
+	zapcore.LevelEnabler
+}
+
+func (c failingCore) With([]zapcore.Field) zapcore.Core { return c }
+
+func (c failingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checked.AddCore(entry, c)
+}
+
+func (c failingCore) Write(zapcore.Entry, []zapcore.Field) error { return errors.New("disk full") }
+
+func (c failingCore) Sync() error { return nil }
+
+func TestCoreWriteError(t *testing.T) {
+	var sink bytes.Buffer
+	logger := zap.New(NewCore(failingCore{zapcore.InfoLevel}), zap.ErrorOutput(zapcore.AddSync(&sink)))
+
+	logger.Info("hello", Context(goctxid.NewContext(context.Background(), "test-id")))
+
+	if got := sink.String(); !strings.HasSuffix(got, " write error: disk full\n") {
+		t.Errorf("ErrorOutput = %q, want the wrapped core's write error", got)
+	}
+}
+
+func TestCoreDoesNotModifyFields(t *testing.T) {
+	logger, _ := newObservedContextLogger()
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+	fields := []zap.Field{Context(ctx)}
+
+	logger.Info("hello", fields...)
+
+	if fields[0].Key != contextFieldKey {
+		t.Errorf("Caller's field was modified: %v", fields[0])
+	}
+}
+
+func TestContextWithoutCore(t *testing.T) {
+	logger, logs := newObservedLogger()
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	logger.Info("hello", Context(ctx))
+
+	if got := logs.All()[0].ContextMap(); len(got) != 0 {
+		t.Errorf("Expected the context field to be skipped, got %v", got)
+	}
+}
+
+func TestConcurrentLogging(t *testing.T) {
+	logger, logs := newObservedContextLogger()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id := goctxid.DefaultGenerator()
+			ctx := goctxid.NewContext(context.Background(), id)
+			logger.Info(id, Context(ctx))
+		}()
+	}
+	wg.Wait()
+
+	for _, entry := range logs.All() {
+		if got := entry.ContextMap()[DefaultKey]; got != entry.Message {
+			t.Errorf("%s = %v, want %v", DefaultKey, got, entry.Message)
+		}
+	}
+}
+
+func TestWithNetHTTPAdapter(t *testing.T) {
+	logger, logs := newObservedLogger()
+
+	handler := goctxid_nethttp.New()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Logger(r.Context(), logger).Info("handling request")
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(goctxid.DefaultHeaderKey, "test-id")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got := logs.All()[0].ContextMap()[DefaultKey]; got != "test-id" {
+		t.Errorf("%s = %v, want test-id", DefaultKey, got)
+	}
+}
+
+func TestWithFiberNativeAdapter(t *testing.T) {
+	logger, logs := newObservedLogger()
+
+	app := fiber.New()
+	app.Use(goctxid_fibernative.New())
+	app.Get("/", func(c *fiber.Ctx) error {
+		logger.Info("handling request", Field(goctxid_fibernative.MustFromLocals(c)))
+		return c.SendString("OK")
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(goctxid.DefaultHeaderKey, "test-id")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if got := logs.All()[0].ContextMap()[DefaultKey]; got != "test-id" {
+		t.Errorf("%s = %v, want test-id", DefaultKey, got)
+	}
+}
+
+func BenchmarkLogger(b *testing.B) {
+	logger := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(io.Discard), zapcore.InfoLevel))
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Logger(ctx, logger).Info("benchmark")
+	}
+}
+
+func BenchmarkCore(b *testing.B) {
+	logger := zap.New(NewCore(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(io.Discard), zapcore.InfoLevel)))
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("benchmark", Context(ctx))
+	}
+}
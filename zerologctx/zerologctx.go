@@ -0,0 +1,88 @@
+// Package zerologctx integrates goctxid with github.com/rs/zerolog.
+//
+// There are two ways to log the correlation ID:
+//
+//	// 1. A hook that reads the ID from the event's context
+//	logger := zerolog.New(os.Stdout).Hook(zerologctx.NewHook())
+//	logger.Info().Ctx(ctx).Msg("Fetching user")
+//
+//	// 2. An ID-enriched logger attached to the request context by a middleware,
+//	// retrieved in handlers with zerolog.Ctx
+//	r = r.WithContext(zerologctx.WithContext(r.Context(), logger))
+//	zerolog.Ctx(r.Context()).Info().Msg("Fetching user")
+package zerologctx
+
+import (
+	"context"
+
+	"github.com/hiiamtin/goctxid"
+	"github.com/rs/zerolog"
+)
+
+// DefaultKey is the default field key used for the correlation ID
+const DefaultKey = "correlation_id"
+
+// Config defines the options of NewHook, WithID and WithContext
+type Config struct {
+	// Key is the field key used for the correlation ID
+	//
+	// Optional. Default: "correlation_id"
+	Key string
+}
+
+// configDefault is a helper function that merges the provided config with the default config
+func configDefault(config ...Config) Config {
+
+	var cfg Config
+
+	// If a config is provided, use it
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
+	// Check and fill in default values
+	if cfg.Key == "" {
+		cfg.Key = DefaultKey
+	}
+
+	return cfg
+}
+
+// Hook is a zerolog.Hook that adds the correlation ID from the event's context,
+// set with Event.Ctx or Context.Ctx. Events without a correlation ID in their
+// context are left unchanged.
+type Hook struct {
+	key string
+}
+
+// NewHook creates a Hook
+func NewHook(config ...Config) Hook {
+	cfg := configDefault(config...)
+	return Hook{key: cfg.Key}
+}
+
+// Run implements zerolog.Hook
+func (h Hook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	if id := goctxid.MustFromContext(e.GetCtx()); id != "" {
+		e.Str(h.key, id)
+	}
+}
+
+// WithID returns a child of logger with the correlation ID under Config.Key
+// (DefaultKey unless configured), so it matches the field added by a Hook with
+// the same Config. Use it with adapters that do not store the ID in a context,
+// such as fibernative. logger is returned unchanged when id is empty.
+func WithID(logger zerolog.Logger, id string, config ...Config) zerolog.Logger {
+	if id == "" {
+		return logger
+	}
+	cfg := configDefault(config...)
+	return logger.With().Str(cfg.Key, id).Logger()
+}
+
+// WithContext returns a copy of ctx carrying a child of logger enriched with the
+// correlation ID from ctx (see WithID). Call it in a middleware registered after
+// the goctxid middleware, then use zerolog.Ctx(ctx) in handlers.
+func WithContext(ctx context.Context, logger zerolog.Logger, config ...Config) context.Context {
+	return WithID(logger, goctxid.MustFromContext(ctx), config...).WithContext(ctx)
+}
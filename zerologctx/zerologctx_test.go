@@ -0,0 +1,292 @@
+package zerologctx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/hiiamtin/goctxid"
+	goctxid_fibernative "github.com/hiiamtin/goctxid/adapters/fibernative"
+	goctxid_nethttp "github.com/hiiamtin/goctxid/adapters/nethttp"
+	"github.com/rs/zerolog"
+)
+
+// decode parses a single JSON log record
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Failed to decode log record %q: %v", buf.String(), err)
+	}
+	return record
+}
+
+func TestHook(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      []Config
+		ctx         context.Context
+		expectedKey string
+		expectedID  any
+	}{
+		{
+			name:        "adds ID from event context",
+			ctx:         goctxid.NewContext(context.Background(), "test-id"),
+			expectedKey: DefaultKey,
+			expectedID:  "test-id",
+		},
+		{
+			name:        "uses custom key",
+			config:      []Config{{Key: "request_id"}},
+			ctx:         goctxid.NewContext(context.Background(), "test-id"),
+			expectedKey: "request_id",
+			expectedID:  "test-id",
+		},
+		{
+			name:        "empty config uses default key",
+			config:      []Config{{}},
+			ctx:         goctxid.NewContext(context.Background(), "test-id"),
+			expectedKey: DefaultKey,
+			expectedID:  "test-id",
+		},
+		{
+			name:        "skips context without ID",
+			ctx:         context.Background(),
+			expectedKey: DefaultKey,
+			expectedID:  nil,
+		},
+		{
+			name:        "skips empty ID",
+			ctx:         goctxid.NewContext(context.Background(), ""),
+			expectedKey: DefaultKey,
+			expectedID:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := zerolog.New(&buf).Hook(NewHook(tt.config...))
+
+			logger.Info().Ctx(tt.ctx).Str("user_id", "42").Msg("hello")
+
+			record := decode(t, &buf)
+			if record[tt.expectedKey] != tt.expectedID {
+				t.Errorf("%s = %v, want %v", tt.expectedKey, record[tt.expectedKey], tt.expectedID)
+			}
+			if record["user_id"] != "42" {
+				t.Errorf("user_id = %v, want 42", record["user_id"])
+			}
+		})
+	}
+}
+
+func TestHookWithoutContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Hook(NewHook())
+
+	logger.Info().Msg("hello")
+
+	if record := decode(t, &buf); record[DefaultKey] != nil {
+		t.Errorf("Unexpected %s in %v", DefaultKey, record)
+	}
+}
+
+func TestHookWithLoggerContext(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+	logger := zerolog.New(&buf).Hook(NewHook()).With().Ctx(ctx).Logger()
+
+	logger.Info().Msg("hello")
+
+	if record := decode(t, &buf); record[DefaultKey] != "test-id" {
+		t.Errorf("%s = %v, want test-id", DefaultKey, record[DefaultKey])
+	}
+}
+
+func TestWithID(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		expectedID any
+	}{
+		{name: "adds ID", id: "test-id", expectedID: "test-id"},
+		{name: "skips empty ID", id: "", expectedID: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := WithID(zerolog.New(&buf), tt.id)
+
+			logger.Info().Msg("hello")
+
+			if record := decode(t, &buf); record[DefaultKey] != tt.expectedID {
+				t.Errorf("%s = %v, want %v", DefaultKey, record[DefaultKey], tt.expectedID)
+			}
+		})
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		expectedID any
+	}{
+		{
+			name:       "attaches enriched logger",
+			ctx:        goctxid.NewContext(context.Background(), "test-id"),
+			expectedID: "test-id",
+		},
+		{
+			name:       "attaches plain logger without ID",
+			ctx:        context.Background(),
+			expectedID: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx := WithContext(tt.ctx, zerolog.New(&buf))
+
+			zerolog.Ctx(ctx).Info().Msg("hello")
+
+			if record := decode(t, &buf); record[DefaultKey] != tt.expectedID {
+				t.Errorf("%s = %v, want %v", DefaultKey, record[DefaultKey], tt.expectedID)
+			}
+			if id := goctxid.MustFromContext(ctx); id != goctxid.MustFromContext(tt.ctx) {
+				t.Errorf("Correlation ID = %v, want %v", id, goctxid.MustFromContext(tt.ctx))
+			}
+		})
+	}
+}
+
+func TestCustomKeyMatchesHook(t *testing.T) {
+	cfg := Config{Key: "request_id"}
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	var hookBuf, idBuf, ctxBuf bytes.Buffer
+	hookLogger := zerolog.New(&hookBuf).Hook(NewHook(cfg))
+	hookLogger.Info().Ctx(ctx).Msg("hello")
+	idLogger := WithID(zerolog.New(&idBuf), "test-id", cfg)
+	idLogger.Info().Msg("hello")
+	zerolog.Ctx(WithContext(ctx, zerolog.New(&ctxBuf), cfg)).Info().Msg("hello")
+
+	for name, buf := range map[string]*bytes.Buffer{"NewHook": &hookBuf, "WithID": &idBuf, "WithContext": &ctxBuf} {
+		record := decode(t, buf)
+		if record["request_id"] != "test-id" {
+			t.Errorf("%s: request_id = %v, want test-id", name, record["request_id"])
+		}
+		if _, ok := record[DefaultKey]; ok {
+			t.Errorf("%s: unexpected %s field", name, DefaultKey)
+		}
+	}
+}
+
+func TestConcurrentLogging(t *testing.T) {
+	// zerolog does not serialize writes, so use a thread-safe writer
+	var buf bytes.Buffer
+	logger := zerolog.New(zerolog.SyncWriter(&buf)).Hook(NewHook())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := goctxid.NewContext(context.Background(), goctxid.DefaultGenerator())
+			logger.Info().Ctx(ctx).Msg("concurrent")
+		}()
+	}
+	wg.Wait()
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 50 {
+		t.Fatalf("Expected 50 records, got %d", len(lines))
+	}
+	for _, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("Failed to decode %q: %v", line, err)
+		}
+		if id, _ := record[DefaultKey].(string); !goctxid.ValidateUUID(id) {
+			t.Errorf("Expected UUID correlation ID, got %v", record[DefaultKey])
+		}
+	}
+}
+
+func TestWithNetHTTPAdapter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	// Attach the logger in a middleware registered after the goctxid middleware
+	app := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zerolog.Ctx(r.Context()).Info().Msg("handling request")
+	})
+	withLogger := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(WithContext(r.Context(), logger)))
+		})
+	}
+	handler := goctxid_nethttp.New()(withLogger(app))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(goctxid.DefaultHeaderKey, "test-id")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if record := decode(t, &buf); record[DefaultKey] != "test-id" {
+		t.Errorf("%s = %v, want test-id", DefaultKey, record[DefaultKey])
+	}
+}
+
+func TestWithFiberNativeAdapter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+
+	app := fiber.New()
+	app.Use(goctxid_fibernative.New())
+	app.Get("/", func(c *fiber.Ctx) error {
+		log := WithID(logger, goctxid_fibernative.MustFromLocals(c))
+		log.Info().Msg("handling request")
+		return c.SendString("OK")
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(goctxid.DefaultHeaderKey, "test-id")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if record := decode(t, &buf); record[DefaultKey] != "test-id" {
+		t.Errorf("%s = %v, want test-id", DefaultKey, record[DefaultKey])
+	}
+}
+
+func BenchmarkHook(b *testing.B) {
+	logger := zerolog.New(io.Discard).Hook(NewHook())
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info().Ctx(ctx).Msg("benchmark")
+	}
+}
+
+func BenchmarkWithContext(b *testing.B) {
+	logger := zerolog.New(io.Discard)
+	ctx := goctxid.NewContext(context.Background(), "test-id")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		zerolog.Ctx(WithContext(ctx, logger)).Info().Msg("benchmark")
+	}
+}